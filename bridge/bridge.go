@@ -0,0 +1,227 @@
+// Package bridge links GeckoTerminal networks and tokens with CoinGecko asset platforms and coins.
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+// maxNetworkPages bounds the pages fetched from GetNetworks API in case the pagination links never end.
+const maxNetworkPages = 50
+
+// ErrNotMapped is returned when a GeckoTerminal network has no CoinGecko asset platform or the other way around.
+var ErrNotMapped = errors.New("not mapped between geckoterminal and coingecko")
+
+// TokenCoin is the CoinGecko asset platform and coin of a GeckoTerminal token. CoinID is empty if the token is not
+// listed on CoinGecko.
+type TokenCoin struct {
+	Network         string `json:"network"`
+	TokenAddress    string `json:"token_address"`
+	AssetPlatformID string `json:"asset_platform_id"`
+	CoinID          string `json:"coin_id"`
+}
+
+// NetworkPools is the top pools of a coin on one GeckoTerminal network.
+type NetworkPools struct {
+	Network         string                       `json:"network"`
+	AssetPlatformID string                       `json:"asset_platform_id"`
+	TokenAddress    string                       `json:"token_address"`
+	Pools           []geckoterminal.PoolDataItem `json:"pools"`
+}
+
+// Bridge resolves GeckoTerminal tokens into CoinGecko coins and the other way around. The mapping between
+// GeckoTerminal networks and CoinGecko asset platforms is loaded from GetNetworks API on first use and cached.
+type Bridge struct {
+	cg *coingecko.Client
+	gt *geckoterminal.Client
+
+	mu        sync.Mutex
+	loading   chan struct{}     // closed when the running load of the networks is done
+	platforms map[string]string // network -> asset platform
+	networks  map[string]string // asset platform -> network
+}
+
+// New creates a Bridge.
+func New(cg *coingecko.Client, gt *geckoterminal.Client) *Bridge {
+	return &Bridge{cg: cg, gt: gt}
+}
+
+// AssetPlatform returns the CoinGecko asset platform id of GeckoTerminal network, e.g. ethereum for eth.
+func (b *Bridge) AssetPlatform(ctx context.Context, network string) (string, error) {
+	if network == "" {
+		return "", fmt.Errorf("network should not be empty")
+	}
+	if err := b.loadNetworks(ctx); err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	platform, ok := b.platforms[network]
+	if !ok {
+		return "", fmt.Errorf("network %s has no coingecko asset platform: %w", network, ErrNotMapped)
+	}
+	return platform, nil
+}
+
+// Network returns the GeckoTerminal network of CoinGecko asset platform id, e.g. eth for ethereum.
+func (b *Bridge) Network(ctx context.Context, assetPlatformID string) (string, error) {
+	if assetPlatformID == "" {
+		return "", fmt.Errorf("asset platform id should not be empty")
+	}
+	if err := b.loadNetworks(ctx); err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	network, ok := b.networks[assetPlatformID]
+	if !ok {
+		return "", fmt.Errorf("asset platform %s has no geckoterminal network: %w", assetPlatformID, ErrNotMapped)
+	}
+	return network, nil
+}
+
+// TokenCoin returns the CoinGecko asset platform and coin of the token at address on GeckoTerminal network.
+// The coin id reported by GeckoTerminal is used, CoinGecko contract API is called if it is missing. CoinID is empty
+// if the contract API does not find the token, its other errors are returned.
+func (b *Bridge) TokenCoin(ctx context.Context, network, address string) (*TokenCoin, error) {
+	if address == "" {
+		return nil, fmt.Errorf("token address should not be empty")
+	}
+	platform, err := b.AssetPlatform(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TokenCoin{Network: network, TokenAddress: address, AssetPlatformID: platform}
+	token, err := b.gt.GetSpecificTokenOnOneNetwork(ctx, network, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token.Data.Attributes.CoingeckoCoinID != "" {
+		result.CoinID = token.Data.Attributes.CoingeckoCoinID
+		return result, nil
+	}
+
+	coin, err := b.cg.GetCoinInfoByContractAddress(ctx, platform, address)
+	if util.StatusCode(err) == http.StatusNotFound {
+		// the token is not listed on coingecko, keep the asset platform only.
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.CoinID = coin.ID
+	return result, nil
+}
+
+// CoinPools returns the top pools of CoinGecko coin id on each GeckoTerminal network it is deployed to, ordered by
+// network. Platforms without GeckoTerminal network are skipped.
+func (b *Bridge) CoinPools(ctx context.Context, id string) ([]NetworkPools, error) {
+	coin, err := b.cg.GetCoinDataByCoinID(ctx, id, false, false, false, false, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if coin.Platforms == nil {
+		return nil, nil
+	}
+
+	platforms := make([]string, 0, len(*coin.Platforms))
+	for platform, address := range *coin.Platforms {
+		if platform != "" && address != "" {
+			platforms = append(platforms, platform)
+		}
+	}
+	sort.Strings(platforms)
+
+	var result []NetworkPools
+	for _, platform := range platforms {
+		network, err := b.Network(ctx, platform)
+		if errors.Is(err, ErrNotMapped) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		address := (*coin.Platforms)[platform]
+		pools, err := b.gt.GetTop20PoolsForOneToken(ctx, network, address, nil)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, NetworkPools{
+			Network:         network,
+			AssetPlatformID: platform,
+			TokenAddress:    address,
+			Pools:           pools.Data,
+		})
+	}
+	return result, nil
+}
+
+// loadNetworks loads the networks once. Concurrent callers wait for the running load instead of fetching the
+// networks again, the mutex is not held while fetching.
+func (b *Bridge) loadNetworks(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		if b.platforms != nil {
+			b.mu.Unlock()
+			return nil
+		}
+		loading := b.loading
+		if loading == nil {
+			done := make(chan struct{})
+			b.loading = done
+			b.mu.Unlock()
+
+			platforms, networks, err := b.fetchNetworks(ctx)
+			b.mu.Lock()
+			if err == nil {
+				b.platforms, b.networks = platforms, networks
+			}
+			b.loading = nil
+			b.mu.Unlock()
+			close(done)
+			return err
+		}
+		b.mu.Unlock()
+
+		// the other load may fail, e.g. if its context is canceled, then try again.
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// fetchNetworks fetches the mapping between GeckoTerminal networks and CoinGecko asset platforms.
+func (b *Bridge) fetchNetworks(ctx context.Context) (map[string]string, map[string]string, error) {
+	platforms := make(map[string]string)
+	networks := make(map[string]string)
+	for page := uint(1); page <= maxNetworkPages; page++ {
+		data, err := b.gt.GetNetworks(ctx, page)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, item := range data.Data {
+			if item.Attributes.CoingeckoAssetPlatformID == nil || *item.Attributes.CoingeckoAssetPlatformID == "" {
+				continue
+			}
+			platforms[item.ID] = *item.Attributes.CoingeckoAssetPlatformID
+			networks[*item.Attributes.CoingeckoAssetPlatformID] = item.ID
+		}
+		if len(data.Data) == 0 || data.Next == nil {
+			break
+		}
+	}
+	return platforms, networks, nil
+}
@@ -0,0 +1,179 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	networksResp = `{"data":[
+{"id":"eth","type":"network","attributes":{"name":"Ethereum","coingecko_asset_platform_id":"ethereum"}},
+{"id":"solana","type":"network","attributes":{"name":"Solana","coingecko_asset_platform_id":"solana"}},
+{"id":"unknown","type":"network","attributes":{"name":"Unknown","coingecko_asset_platform_id":null}}
+],"links":{"first":"","prev":null,"next":null,"last":""}}`
+	listedTokenResp   = `{"data":{"id":"eth_0xusdc","type":"token","attributes":{"address":"0xusdc","coingecko_coin_id":"usd-coin"}}}`
+	unlistedTokenResp = `{"data":{"id":"eth_0xnew","type":"token","attributes":{"address":"0xnew","coingecko_coin_id":""}}}`
+	coinResp          = `{"id":"usd-coin","symbol":"usdc","name":"USDC","platforms":{"ethereum":"0xusdc","solana":"EPjF","tron":"TEkx"}}`
+	poolsResp         = `{"data":[{"id":"pool","type":"pool","attributes":{"address":"0xpool","name":"USDC / WETH"}}]}`
+)
+
+// networkCalls counts the calls of GetNetworks API.
+var networkCalls atomic.Int32
+
+func setup(t *testing.T) *Bridge {
+	t.Helper()
+	responses := map[string]string{
+		"/api/v2/networks":                          networksResp,
+		"/api/v2/networks/eth/tokens/0xusdc":        listedTokenResp,
+		"/api/v2/networks/eth/tokens/0xnew":         unlistedTokenResp,
+		"/api/v2/networks/eth/tokens/0xusdc/pools":  poolsResp,
+		"/api/v2/networks/solana/tokens/EPjF/pools": poolsResp,
+		"/api/v3/coins/usd-coin":                    coinResp,
+		"/api/v2/networks/eth/tokens/0xlimited":     unlistedTokenResp,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v3/coins/ethereum/contract/0xlimited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if r.URL.Path == "/api/v2/networks" {
+			networkCalls.Add(1)
+		}
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"coin not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(server.Close)
+
+	cg := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithBaseURL(server.URL+"/api/v2"))
+	return New(cg, gt)
+}
+
+func TestBridge_TokenCoin(t *testing.T) {
+	b := setup(t)
+	cases := []struct {
+		name         string
+		network      string
+		address      string
+		wantedIsErr  bool
+		wantedResult TokenCoin
+	}{
+		{
+			name:         "listed token",
+			network:      "eth",
+			address:      "0xusdc",
+			wantedResult: TokenCoin{Network: "eth", TokenAddress: "0xusdc", AssetPlatformID: "ethereum", CoinID: "usd-coin"},
+		},
+		{
+			name:         "unlisted token",
+			network:      "eth",
+			address:      "0xnew",
+			wantedResult: TokenCoin{Network: "eth", TokenAddress: "0xnew", AssetPlatformID: "ethereum"},
+		},
+		{
+			name:        "rate limited contract api",
+			network:     "eth",
+			address:     "0xlimited",
+			wantedIsErr: true,
+		},
+		{
+			name:        "network without asset platform",
+			network:     "unknown",
+			address:     "0xusdc",
+			wantedIsErr: true,
+		},
+		{
+			name:        "empty address",
+			network:     "eth",
+			wantedIsErr: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := b.TokenCoin(context.TODO(), tt.network, tt.address)
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if *result != tt.wantedResult {
+				t.Fatalf("incorrect result, wanted: %+v, got: %+v", tt.wantedResult, *result)
+			}
+		})
+	}
+}
+
+func TestBridge_ConcurrentLoad(t *testing.T) {
+	b := setup(t)
+	networkCalls.Store(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := b.AssetPlatform(context.TODO(), "eth"); err != nil {
+				t.Errorf("error should be nil, got: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls := networkCalls.Load(); calls != 1 {
+		t.Fatalf("networks should be fetched once, got: %d", calls)
+	}
+}
+
+func TestBridge_CoinPools(t *testing.T) {
+	b := setup(t)
+	result, err := b.CoinPools(context.TODO(), "usd-coin")
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("incorrect network number, wanted: 2, got: %d", len(result))
+	}
+	if result[0].Network != "eth" || result[0].TokenAddress != "0xusdc" || result[1].Network != "solana" {
+		t.Fatalf("incorrect networks, got: %+v", result)
+	}
+	if len(result[1].Pools) != 1 || result[1].Pools[0].Attributes.Address != "0xpool" {
+		t.Fatalf("incorrect pools, got: %+v", result[1].Pools)
+	}
+}
+
+func TestBridge_CoinPoolsNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v3/coins/usd-coin" {
+			_, _ = w.Write([]byte(coinResp))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cg := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithBaseURL(server.URL+"/api/v2"))
+	result, err := New(cg, gt).CoinPools(context.TODO(), "usd-coin")
+	if util.StatusCode(err) != http.StatusInternalServerError || result != nil {
+		t.Fatalf("error of loading networks should be returned, got: %+v, %v", result, err)
+	}
+	if _, err = New(cg, gt).Network(context.TODO(), "ethereum"); errors.Is(err, ErrNotMapped) {
+		t.Fatalf("error of loading networks should not be ErrNotMapped, got: %v", err)
+	}
+}
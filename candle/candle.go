@@ -0,0 +1,152 @@
+// Package candle provides resampling and gap-filling utilities for OHLC(V) data returned by CoinGecko and
+// GeckoTerminal APIs.
+package candle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+// Candle is an OHLCV candle, Time is the open time of the candle in UTC.
+type Candle struct {
+	Time   time.Time `json:"time"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"`
+	// Filled is true if the candle is forward-filled from the previous candle because there is no data in its bucket.
+	Filled bool `json:"filled,omitempty"`
+}
+
+// FromCoinOHLC converts data returned by GetCoinOHLCByCoinID API into candles. interval is the candle size of the
+// data (30 minutes, 4 hours or 4 days, depending on days param).
+//
+// CoinGecko uses the close time as timestamp of a candle, so it is moved back by interval to get the open time.
+// CoinGecko returns no volume, Volume of the candles is 0.
+func FromCoinOHLC(items []coingecko.CoinOHLCResponse, interval time.Duration) []Candle {
+	candles := make([]Candle, 0, len(items))
+	for _, item := range items {
+		candles = append(candles, Candle{
+			Time:  item.Time().Add(-interval),
+			Open:  item.Open(),
+			High:  item.High(),
+			Low:   item.Low(),
+			Close: item.Close(),
+		})
+	}
+	sortCandles(candles)
+	return candles
+}
+
+// FromOHLCV converts data returned by GetOHLCV API into candles in chronological order.
+func FromOHLCV(items []geckoterminal.OHLCVItem) []Candle {
+	candles := make([]Candle, 0, len(items))
+	for _, item := range items {
+		candles = append(candles, Candle{
+			Time:   item.Time(),
+			Open:   item.Open(),
+			High:   item.High(),
+			Low:    item.Low(),
+			Close:  item.Close(),
+			Volume: item.Volume(),
+		})
+	}
+	sortCandles(candles)
+	return candles
+}
+
+// Align returns the start of the UTC bucket of size interval which t falls in. Buckets are aligned to UTC
+// boundaries, e.g. hours start at minute 0, days start at 00:00 UTC and weeks start on Monday 00:00 UTC.
+func Align(t time.Time, interval time.Duration) time.Time {
+	return t.UTC().Truncate(interval)
+}
+
+// Resample aggregates candles into candles of a larger interval, e.g. 1h from 30m, weekly from daily or 3m from 1m.
+// interval should be a multiple of the interval of the input candles; a candle is put into the bucket which its
+// open time falls in.
+//
+// Buckets are aligned to UTC boundaries(see Align) and missing buckets are forward-filled(see FillGaps).
+func Resample(candles []Candle, interval time.Duration) ([]Candle, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval should be greater than 0")
+	}
+	if len(candles) == 0 {
+		return nil, nil
+	}
+
+	sorted := make([]Candle, len(candles))
+	copy(sorted, candles)
+	sortCandles(sorted)
+
+	var result []Candle
+	for _, c := range sorted {
+		bucket := Align(c.Time, interval)
+		if len(result) == 0 || !result[len(result)-1].Time.Equal(bucket) {
+			result = append(result, Candle{
+				Time:   bucket,
+				Open:   c.Open,
+				High:   c.High,
+				Low:    c.Low,
+				Close:  c.Close,
+				Volume: c.Volume,
+				Filled: c.Filled,
+			})
+			continue
+		}
+
+		last := &result[len(result)-1]
+		last.High = max(last.High, c.High)
+		last.Low = min(last.Low, c.Low)
+		last.Close = c.Close
+		last.Volume += c.Volume
+		// a bucket is only filled if all its candles are filled.
+		last.Filled = last.Filled && c.Filled
+	}
+	return FillGaps(result, interval)
+}
+
+// FillGaps forward-fills missing buckets between candles which are aligned to interval. A filled candle uses the
+// close price of the previous candle as its open, high, low and close price, has zero volume and is flagged with
+// Filled.
+func FillGaps(candles []Candle, interval time.Duration) ([]Candle, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval should be greater than 0")
+	}
+	if len(candles) == 0 {
+		return nil, nil
+	}
+
+	result := make([]Candle, 0, len(candles))
+	for i, c := range candles {
+		if i != 0 {
+			prev := result[len(result)-1]
+			if !c.Time.After(prev.Time) {
+				return nil, fmt.Errorf("candles should be in chronological order without duplicates, got %s after %s",
+					c.Time, prev.Time)
+			}
+			for t := prev.Time.Add(interval); t.Before(c.Time); t = t.Add(interval) {
+				result = append(result, Candle{
+					Time:   t,
+					Open:   prev.Close,
+					High:   prev.Close,
+					Low:    prev.Close,
+					Close:  prev.Close,
+					Filled: true,
+				})
+			}
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func sortCandles(candles []Candle) {
+	sort.SliceStable(candles, func(i, j int) bool {
+		return candles[i].Time.Before(candles[j].Time)
+	})
+}
@@ -0,0 +1,113 @@
+package candle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+var base = time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFromCoinOHLC(t *testing.T) {
+	closeTime := float64(base.Add(30 * time.Minute).UnixMilli())
+	candles := FromCoinOHLC([]coingecko.CoinOHLCResponse{{closeTime, 1, 2, 0.5, 1.5}}, 30*time.Minute)
+	wanted := []Candle{{Time: base, Open: 1, High: 2, Low: 0.5, Close: 1.5}}
+	if !reflect.DeepEqual(candles, wanted) {
+		t.Fatalf("incorrect candles, wanted result: %+v, got result: %+v", wanted, candles)
+	}
+}
+
+func TestFromOHLCV(t *testing.T) {
+	candles := FromOHLCV([]geckoterminal.OHLCVItem{
+		{float64(base.Add(time.Minute).Unix()), 2, 3, 1, 2.5, 20},
+		{float64(base.Unix()), 1, 2, 0.5, 1.5, 10},
+	})
+	wanted := []Candle{
+		{Time: base, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10},
+		{Time: base.Add(time.Minute), Open: 2, High: 3, Low: 1, Close: 2.5, Volume: 20},
+	}
+	if !reflect.DeepEqual(candles, wanted) {
+		t.Fatalf("incorrect candles, wanted result: %+v, got result: %+v", wanted, candles)
+	}
+}
+
+func TestResample(t *testing.T) {
+	cases := []struct {
+		name         string
+		candles      []Candle
+		interval     time.Duration
+		wantedIsErr  bool
+		wantedResult []Candle
+	}{
+		{
+			name: "1h from 30m",
+			candles: []Candle{
+				{Time: base, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10},
+				{Time: base.Add(30 * time.Minute), Open: 1.5, High: 3, Low: 1, Close: 2, Volume: 5},
+				{Time: base.Add(time.Hour), Open: 2, High: 2.5, Low: 1.5, Close: 2.2, Volume: 1},
+			},
+			interval: time.Hour,
+			wantedResult: []Candle{
+				{Time: base, Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 15},
+				{Time: base.Add(time.Hour), Open: 2, High: 2.5, Low: 1.5, Close: 2.2, Volume: 1},
+			},
+		},
+		{
+			name: "3m from 1m with gap",
+			candles: []Candle{
+				{Time: base.Add(time.Minute), Open: 1, High: 1, Low: 1, Close: 1, Volume: 1},
+				{Time: base.Add(7 * time.Minute), Open: 2, High: 2, Low: 2, Close: 2, Volume: 1},
+			},
+			interval: 3 * time.Minute,
+			wantedResult: []Candle{
+				{Time: base, Open: 1, High: 1, Low: 1, Close: 1, Volume: 1},
+				{Time: base.Add(3 * time.Minute), Open: 1, High: 1, Low: 1, Close: 1, Filled: true},
+				{Time: base.Add(6 * time.Minute), Open: 2, High: 2, Low: 2, Close: 2, Volume: 1},
+			},
+		},
+		{
+			name: "weekly from daily starts on monday",
+			candles: []Candle{
+				{Time: base, Open: 1, High: 1, Low: 1, Close: 1, Volume: 1},
+				{Time: base.AddDate(0, 0, 1), Open: 2, High: 2, Low: 2, Close: 2, Volume: 1},
+			},
+			interval: 7 * 24 * time.Hour,
+			wantedResult: []Candle{
+				{Time: time.Date(2023, 10, 30, 0, 0, 0, 0, time.UTC), Open: 1, High: 2, Low: 1, Close: 2, Volume: 2},
+			},
+		},
+		{
+			name:        "invalid interval",
+			candles:     []Candle{{Time: base}},
+			interval:    0,
+			wantedIsErr: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Resample(tt.candles, tt.interval)
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.wantedResult) {
+				t.Fatalf("incorrect candles, wanted result: %+v, got result: %+v", tt.wantedResult, result)
+			}
+		})
+	}
+}
+
+func TestFillGaps_Unordered(t *testing.T) {
+	_, err := FillGaps([]Candle{{Time: base.Add(time.Hour)}, {Time: base}}, time.Hour)
+	if err == nil {
+		t.Fatal("error should not be nil")
+	}
+}
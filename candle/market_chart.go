@@ -0,0 +1,100 @@
+package candle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+// VolumeMode selects how candle volume is derived from TotalVolumes of market chart data, which is a rolling 24h
+// volume rather than the volume traded within each point.
+type VolumeMode int
+
+const (
+	// VolumeSnapshot uses the last rolling 24h volume within the bucket.
+	VolumeSnapshot VolumeMode = iota
+	// VolumeDelta uses the difference between the last rolling 24h volume of the bucket and the one of the previous
+	// bucket(the first value of the bucket for the first bucket). It approximates the volume traded within the
+	// bucket, negative differences are reported as 0.
+	VolumeDelta
+)
+
+// FromMarketChart builds candles of interval from data returned by GetCoinMarketChartByCoinID or
+// GetCoinMarketChartRangeByCoinID API. Open, high, low and close come from Prices, volume comes from TotalVolumes
+// according to mode.
+//
+// Buckets are aligned to UTC boundaries(see Align), buckets without price points are skipped; use FillGaps to
+// forward-fill them.
+func FromMarketChart(data *coingecko.CoinMarketChartDataResponse, interval time.Duration, mode VolumeMode) ([]Candle, error) {
+	if data == nil {
+		return nil, fmt.Errorf("market chart data should not be nil")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval should be greater than 0")
+	}
+	if mode != VolumeSnapshot && mode != VolumeDelta {
+		return nil, fmt.Errorf("invalid volume mode: %d", int(mode))
+	}
+
+	var candles []Candle
+	for _, p := range sortedPoints(data.Prices) {
+		bucket := Align(p.Time, interval)
+		if len(candles) == 0 || !candles[len(candles)-1].Time.Equal(bucket) {
+			candles = append(candles, Candle{Time: bucket, Open: p.Value, High: p.Value, Low: p.Value, Close: p.Value})
+			continue
+		}
+		last := &candles[len(candles)-1]
+		last.High = max(last.High, p.Value)
+		last.Low = min(last.Low, p.Value)
+		last.Close = p.Value
+	}
+
+	volumes := bucketVolumes(sortedPoints(data.TotalVolumes), interval, mode)
+	for i := range candles {
+		candles[i].Volume = volumes[candles[i].Time.UnixNano()]
+	}
+	return candles, nil
+}
+
+// bucketVolumes maps the start of each bucket in unix nanoseconds into its volume.
+func bucketVolumes(points []coingecko.ChartPoint, interval time.Duration, mode VolumeMode) map[int64]float64 {
+	type bucketRange struct {
+		start int64
+		first float64
+		last  float64
+	}
+
+	var buckets []bucketRange
+	for _, p := range points {
+		start := Align(p.Time, interval).UnixNano()
+		if len(buckets) == 0 || buckets[len(buckets)-1].start != start {
+			buckets = append(buckets, bucketRange{start: start, first: p.Value, last: p.Value})
+			continue
+		}
+		buckets[len(buckets)-1].last = p.Value
+	}
+
+	volumes := make(map[int64]float64, len(buckets))
+	for i, b := range buckets {
+		if mode == VolumeSnapshot {
+			volumes[b.start] = b.last
+			continue
+		}
+		prev := b.first
+		if i != 0 {
+			prev = buckets[i-1].last
+		}
+		volumes[b.start] = max(b.last-prev, 0)
+	}
+	return volumes
+}
+
+func sortedPoints(items []coingecko.ChartItem) []coingecko.ChartPoint {
+	points := coingecko.ChartPoints(items)
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+	return points
+}
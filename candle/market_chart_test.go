@@ -0,0 +1,74 @@
+package candle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+func chartItem(t time.Time, v float64) coingecko.ChartItem {
+	return coingecko.ChartItem{float64(t.UnixMilli()), v}
+}
+
+func TestFromMarketChart(t *testing.T) {
+	data := &coingecko.CoinMarketChartDataResponse{
+		Prices: []coingecko.ChartItem{
+			chartItem(base.Add(5*time.Minute), 10),
+			chartItem(base.Add(20*time.Minute), 12),
+			chartItem(base.Add(40*time.Minute), 9),
+			chartItem(base.Add(65*time.Minute), 11),
+		},
+		TotalVolumes: []coingecko.ChartItem{
+			chartItem(base.Add(5*time.Minute), 1000),
+			chartItem(base.Add(40*time.Minute), 1100),
+			chartItem(base.Add(65*time.Minute), 1050),
+		},
+	}
+	cases := []struct {
+		name         string
+		mode         VolumeMode
+		wantedIsErr  bool
+		wantedResult []Candle
+	}{
+		{
+			name: "snapshot volume",
+			mode: VolumeSnapshot,
+			wantedResult: []Candle{
+				{Time: base, Open: 10, High: 12, Low: 9, Close: 9, Volume: 1100},
+				{Time: base.Add(time.Hour), Open: 11, High: 11, Low: 11, Close: 11, Volume: 1050},
+			},
+		},
+		{
+			name: "delta volume",
+			mode: VolumeDelta,
+			wantedResult: []Candle{
+				{Time: base, Open: 10, High: 12, Low: 9, Close: 9, Volume: 100},
+				{Time: base.Add(time.Hour), Open: 11, High: 11, Low: 11, Close: 11, Volume: 0},
+			},
+		},
+		{
+			name:        "invalid mode",
+			mode:        VolumeMode(5),
+			wantedIsErr: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FromMarketChart(data, time.Hour, tt.mode)
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.wantedResult) {
+				t.Fatalf("incorrect candles, wanted result: %+v, got result: %+v", tt.wantedResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package cgotel traces the API calls of the coingecko and geckoterminal clients as OpenTelemetry spans.
+//
+// It is a separate module, so that the API clients do not depend on OpenTelemetry:
+//
+//	tracer := cgotel.NewTracer(otel.GetTracerProvider())
+//	cg := coingecko.NewCoinGecko("your_api_key", false, nil, coingecko.WithTracer(tracer))
+//	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithTracer(tracer))
+//
+// Each call is a client span named after the API and the client method, e.g. coingecko.GetCoinDataByCoinID. The
+// context of the span is passed to the http.Client of the API client, so spans of an instrumented transport such as
+// otelhttp are its children.
+package cgotel
+
+import (
+	"context"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bufdata/coingecko-api/util"
+)
+
+// instrumentationName is the name of the tracer of this package.
+const instrumentationName = "github.com/bufdata/coingecko-api/cgotel"
+
+// Attributes of the call spans besides the HTTP and URL semantic conventions.
+const (
+	APIKey        = attribute.Key("coingecko.api")
+	MethodKey     = attribute.Key("coingecko.method")
+	RetriesKey    = attribute.Key("coingecko.retries")
+	CacheHitKey   = attribute.Key("coingecko.cache_hit")
+	statusCodeKey = attribute.Key("http.response.status_code")
+	bodySizeKey   = attribute.Key("http.response.body.size")
+)
+
+// Tracer is a util.Tracer which records API calls as spans of an OpenTelemetry tracer.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a Tracer of provider, e.g. otel.GetTracerProvider().
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: provider.Tracer(instrumentationName)}
+}
+
+// StartCall starts the span of call.
+func (t *Tracer) StartCall(ctx context.Context, call util.CallInfo) (context.Context, util.CallSpan) {
+	attrs := []attribute.KeyValue{
+		APIKey.String(call.API),
+		MethodKey.String(call.Method),
+		attribute.String("http.request.method", "GET"),
+		attribute.String("url.template", call.Template()),
+		attribute.String("url.full", call.Endpoint),
+	}
+	if u, err := url.Parse(call.Endpoint); err == nil {
+		attrs = append(attrs, attribute.String("server.address", u.Hostname()))
+	}
+	ctx, span := t.tracer.Start(ctx, call.API+"."+call.Method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	return ctx, callSpan{span: span}
+}
+
+// callSpan is the span of a call in progress.
+type callSpan struct {
+	span trace.Span
+}
+
+// End records result and ends the span.
+func (s callSpan) End(result util.CallResult) {
+	attrs := []attribute.KeyValue{
+		RetriesKey.Int(result.Retries),
+		CacheHitKey.Bool(result.CacheHit),
+		bodySizeKey.Int(result.ResponseSize),
+	}
+	if result.StatusCode != 0 {
+		attrs = append(attrs, statusCodeKey.Int(result.StatusCode))
+	}
+	s.span.SetAttributes(attrs...)
+	if result.Err != nil {
+		s.span.RecordError(result.Err)
+		s.span.SetStatus(codes.Error, result.Err.Error())
+	}
+	s.span.End()
+}
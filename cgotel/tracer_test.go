@@ -0,0 +1,95 @@
+package cgotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+func TestTracer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v3/coins/bitcoin" {
+			w.Header().Set("X-Cache", "HIT")
+			_, _ = w.Write([]byte(`{"id":"bitcoin"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	cg := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"),
+		coingecko.WithTracer(tracer))
+	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithBaseURL(server.URL+"/api/v2"),
+		geckoterminal.WithTracer(tracer))
+	if _, err := cg.GetCoinDataByCoinID(context.TODO(), "bitcoin", false, false, false, false, false, false); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if _, err := gt.GetNetworks(context.TODO(), 1); err == nil {
+		t.Fatal("error should not be nil")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("incorrect number of spans, wanted: 2, got: %d", len(spans))
+	}
+	cases := []struct {
+		name         string
+		wantedStatus codes.Code
+		wantedAttrs  map[attribute.Key]attribute.Value
+	}{
+		{
+			name:         "coingecko.GetCoinDataByCoinID",
+			wantedStatus: codes.Unset,
+			wantedAttrs: map[attribute.Key]attribute.Value{
+				MethodKey:                   attribute.StringValue("GetCoinDataByCoinID"),
+				"url.template":              attribute.StringValue("/coins/{}"),
+				"http.response.status_code": attribute.IntValue(200),
+				"http.response.body.size":   attribute.IntValue(16),
+				CacheHitKey:                 attribute.BoolValue(true),
+				RetriesKey:                  attribute.IntValue(0),
+			},
+		},
+		{
+			name:         "geckoterminal.GetNetworks",
+			wantedStatus: codes.Error,
+			wantedAttrs: map[attribute.Key]attribute.Value{
+				APIKey:                      attribute.StringValue("geckoterminal"),
+				"url.template":              attribute.StringValue("/networks"),
+				"http.response.status_code": attribute.IntValue(404),
+				CacheHitKey:                 attribute.BoolValue(false),
+			},
+		},
+	}
+	for i, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			span := spans[i]
+			if span.Name() != tt.name || span.SpanKind() != trace.SpanKindClient {
+				t.Fatalf("incorrect span: %s %v", span.Name(), span.SpanKind())
+			}
+			if span.Status().Code != tt.wantedStatus {
+				t.Fatalf("incorrect status, wanted: %v, got: %v", tt.wantedStatus, span.Status().Code)
+			}
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, attr := range span.Attributes() {
+				attrs[attr.Key] = attr.Value
+			}
+			for key, wanted := range tt.wantedAttrs {
+				if attrs[key] != wanted {
+					t.Fatalf("incorrect %s, wanted: %v, got: %v", key, wanted.Emit(), attrs[key].Emit())
+				}
+			}
+		})
+	}
+}
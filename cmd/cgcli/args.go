@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// args parses the flags and positional arguments of a subcommand. Unlike flag.FlagSet, flags may follow positional
+// arguments, e.g. `cgcli price bitcoin --vs eur`.
+type args struct {
+	*flag.FlagSet
+	raw []string
+	pos []string
+}
+
+// parse parses the arguments, it fails unless there are between minArgs and maxArgs positional arguments.
+func (a *args) parse(minArgs, maxArgs int) error {
+	rest := a.raw
+	for {
+		if err := a.FlagSet.Parse(rest); err != nil {
+			return err
+		}
+		rest = a.FlagSet.Args()
+		if len(rest) == 0 {
+			break
+		}
+		a.pos = append(a.pos, rest[0])
+		rest = rest[1:]
+	}
+	if len(a.pos) < minArgs || len(a.pos) > maxArgs {
+		a.FlagSet.Usage()
+		if minArgs == maxArgs {
+			return fmt.Errorf("%s: expected %d arguments, got %d", a.Name(), minArgs, len(a.pos))
+		}
+		return fmt.Errorf("%s: expected %d to %d arguments, got %d", a.Name(), minArgs, maxArgs, len(a.pos))
+	}
+	return nil
+}
+
+// arg returns the i-th positional argument, or an empty string if it is missing.
+func (a *args) arg(i int) string {
+	if i >= len(a.pos) {
+		return ""
+	}
+	return a.pos[i]
+}
+
+// list returns the i-th positional argument as a comma-separated list.
+func (a *args) list(i int) []string {
+	return splitList(a.arg(i))
+}
+
+// splitList splits a comma-separated list, empty items are dropped.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// boolParam converts b into the string form of optional boolean query parameters.
+func boolParam(b bool) string {
+	if b {
+		return "true"
+	}
+	return ""
+}
+
+// optionalInt is an int flag which tells whether it is set.
+type optionalInt struct {
+	value int
+	set   bool
+}
+
+func (o *optionalInt) String() string {
+	if o == nil || !o.set {
+		return ""
+	}
+	return strconv.Itoa(o.value)
+}
+
+func (o *optionalInt) Set(s string) error {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	o.value, o.set = value, true
+	return nil
+}
+
+// parseTime parses unix seconds, RFC 3339 time or a 2006-01-02 date in UTC.
+func parseTime(s string) (time.Time, error) {
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected unix seconds, RFC 3339 time or yyyy-mm-dd date", s)
+	}
+	return t, nil
+}
+
+// parseRange parses the from and to flags of range commands, to defaults to now.
+func parseRange(from, to string) (time.Time, time.Time, error) {
+	if from == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("from should not be empty")
+	}
+	start, err := parseTime(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end := time.Now().UTC()
+	if to != "" {
+		if end, err = parseTime(to); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	return start, end, nil
+}
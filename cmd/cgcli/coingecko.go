@@ -0,0 +1,792 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+// coinGeckoArgs parses a and returns the CoinGecko client.
+func coinGeckoArgs(c *clients, a *args, minArgs, maxArgs int) (*coingecko.Client, error) {
+	if err := a.parse(minArgs, maxArgs); err != nil {
+		return nil, err
+	}
+	return c.coingecko()
+}
+
+// allPages fetches pages from page 1 until the page count reported by the API.
+func allPages[T any](fetch func(page uint) (*[]T, int, error)) ([]T, error) {
+	var all []T
+	for page := uint(1); ; page++ {
+		items, pageCount, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, *items...)
+		if len(*items) == 0 || int(page) >= pageCount {
+			return all, nil
+		}
+	}
+}
+
+// pages fetches page, or all pages if all is set.
+func pages[T any](all bool, page uint, fetch func(page uint) (*[]T, int, error)) (any, error) {
+	if all {
+		return allPages(fetch)
+	}
+	items, _, err := fetch(page)
+	return items, err
+}
+
+// allTickers fetches the tickers of all pages after the first page.
+func allTickers(first []coingecko.TickersItem, pageCount int,
+	fetch func(page uint) ([]coingecko.TickersItem, error)) ([]coingecko.TickersItem, error) {
+	tickers := first
+	for page := uint(2); int(page) <= pageCount; page++ {
+		items, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			break
+		}
+		tickers = append(tickers, items...)
+	}
+	return tickers, nil
+}
+
+var granularities = map[string]coingecko.Granularity{
+	coingecko.GranularityFiveMinutely.String(): coingecko.GranularityFiveMinutely,
+	coingecko.GranularityHourly.String():       coingecko.GranularityHourly,
+	coingecko.GranularityDaily.String():        coingecko.GranularityDaily,
+}
+
+// marketChartRange returns the market chart of a coin between the from and to flags, fetched in chunks of granularity
+// if set.
+func marketChartRange(ctx context.Context, cg *coingecko.Client, id, vs, from, to, granularity, precision string) (
+	*coingecko.CoinMarketChartDataResponse, error) {
+	start, end, err := parseRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if granularity == "" {
+		return cg.GetCoinMarketChartRangeByCoinIDWithTime(ctx, id, vs, start, end, precision)
+	}
+	g, ok := granularities[granularity]
+	if !ok {
+		return nil, fmt.Errorf("invalid granularity %q, valid values: 5-minutely, hourly, daily", granularity)
+	}
+	return cg.GetCoinMarketChartRangeByCoinIDWithGranularity(ctx, id, vs, start, end, g, precision)
+}
+
+var coinGeckoCommands = []command{
+	{
+		name:    "ping",
+		summary: "Check CoinGecko API server status.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.Ping(ctx)
+		},
+	},
+	{
+		name:    "price",
+		usage:   "<ids>",
+		summary: "Get prices of comma-separated coin ids.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs, marketCap, volume, change, updatedAt, precision := priceFlags(a)
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return cg.SimplePrice(ctx, a.list(0), splitList(*vs), boolParam(*marketCap), boolParam(*volume),
+				boolParam(*change), boolParam(*updatedAt), *precision)
+		},
+	},
+	{
+		name:    "token-price",
+		usage:   "<platform> <addresses>",
+		summary: "Get prices of comma-separated token contract addresses on an asset platform.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs, marketCap, volume, change, updatedAt, precision := priceFlags(a)
+			cg, err := coinGeckoArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			return cg.SimpleTokenPrice(ctx, a.arg(0), a.list(1), splitList(*vs), boolParam(*marketCap),
+				boolParam(*volume), boolParam(*change), boolParam(*updatedAt), *precision)
+		},
+	},
+	{
+		name:    "vs-currencies",
+		summary: "List supported vs currencies.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.SimpleSupportedVSCurrencies(ctx)
+		},
+	},
+	{
+		name:    "coins",
+		summary: "List all supported coins with id, name and symbol.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			platforms := a.Bool("platforms", false, "include platform contract addresses")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListCoinsInfo(ctx, *platforms)
+		},
+	},
+	{
+		name:    "markets",
+		summary: "List coins with price, market cap and volume.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs := a.String("vs", "usd", "target currency")
+			ids := a.String("ids", "", "comma-separated coin ids")
+			category := a.String("category", "", "coin category")
+			order := a.String("order", "", "sort order, e.g. market_cap_desc or volume_desc")
+			perPage := a.Uint("per-page", 100, "results per page, 1 to 250")
+			page := a.Uint("page", 1, "page number")
+			sparkline := a.Bool("sparkline", false, "include 7d sparkline")
+			priceChange := a.String("price-change", "", "comma-separated price change timeframes, e.g. 1h,24h,7d")
+			locale := a.String("locale", "", "locale of names")
+			precision := a.String("precision", "", "decimal places of prices")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListCoinsMarketsData(ctx, *vs, splitList(*ids), *category, *order, *perPage, *page, *sparkline,
+				splitList(*priceChange), *locale, *precision)
+		},
+	},
+	{
+		name:    "coin",
+		usage:   "<id>",
+		summary: "Get current data of a coin.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			localization := a.Bool("localization", false, "include localized names and descriptions")
+			tickers := a.Bool("tickers", false, "include tickers")
+			marketData := a.Bool("market-data", true, "include market data")
+			community := a.Bool("community", false, "include community data")
+			developer := a.Bool("developer", false, "include developer data")
+			sparkline := a.Bool("sparkline", false, "include 7d sparkline")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetCoinDataByCoinID(ctx, a.arg(0), *localization, *tickers, *marketData, *community, *developer,
+				*sparkline)
+		},
+	},
+	{
+		name:    "coin-tickers",
+		usage:   "<id>",
+		summary: "Get tickers of a coin, 100 per page.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			exchanges := a.String("exchanges", "", "comma-separated exchange ids")
+			logo := a.Bool("logo", false, "include exchange logos")
+			page := a.Uint("page", 1, "page number")
+			order := a.String("order", "", "sort order: trust_score_desc, trust_score_asc or volume_desc")
+			depth := a.Bool("depth", false, "include 2% order book depth")
+			all := a.Bool("all", false, "fetch all pages")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			fetch := func(page uint) (*coingecko.CoinTickersResponse, int, error) {
+				return cg.GetCoinTickersByCoinID(ctx, a.arg(0), *exchanges, *logo, page, *order, *depth)
+			}
+			if !*all {
+				data, _, err := fetch(*page)
+				return data, err
+			}
+			data, pageCount, err := fetch(1)
+			if err != nil {
+				return nil, err
+			}
+			data.Tickers, err = allTickers(data.Tickers, pageCount, func(page uint) ([]coingecko.TickersItem, error) {
+				data, _, err := fetch(page)
+				if err != nil {
+					return nil, err
+				}
+				return data.Tickers, nil
+			})
+			return data, err
+		},
+	},
+	{
+		name:    "coin-history",
+		usage:   "<id> <date>",
+		summary: "Get price, market cap and volume of a coin at a date, e.g. 2023-11-01 or 01-11-2023.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			localization := a.Bool("localization", false, "include localized names")
+			cg, err := coinGeckoArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			if date, err := parseTime(a.arg(1)); err == nil {
+				return cg.GetCoinHistoryDataByCoinIDWithTime(ctx, a.arg(0), date, *localization)
+			}
+			return cg.GetCoinHistoryDataByCoinID(ctx, a.arg(0), a.arg(1), *localization)
+		},
+	},
+	{
+		name:    "market-chart",
+		usage:   "<id>",
+		summary: "Get price, market cap and volume chart of a coin over the last days.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs := a.String("vs", "usd", "target currency")
+			days := a.String("days", "1", "number of days or max")
+			interval := a.String("interval", "", "data interval, e.g. daily")
+			precision := a.String("precision", "", "decimal places of prices")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetCoinMarketChartByCoinID(ctx, a.arg(0), *vs, *days, *interval, *precision)
+		},
+	},
+	{
+		name:    "market-chart-range",
+		usage:   "<id> --from <time> [--to <time>]",
+		summary: "Get price, market cap and volume chart of a coin within a time range.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs := a.String("vs", "usd", "target currency")
+			from := a.String("from", "", "start time: unix seconds, RFC 3339 time or yyyy-mm-dd")
+			to := a.String("to", "", "end time, default: now")
+			granularity := a.String("granularity", "", "data interval: 5-minutely, hourly or daily, "+
+				"long ranges are fetched in chunks")
+			precision := a.String("precision", "", "decimal places of prices")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return marketChartRange(ctx, cg, a.arg(0), *vs, *from, *to, *granularity, *precision)
+		},
+	},
+	{
+		name:    "ohlc",
+		usage:   "<id>",
+		summary: "Get OHLC candles of a coin over the last days.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs := a.String("vs", "usd", "target currency")
+			days := a.String("days", "1", "number of days: 1, 7, 14, 30, 90, 180, 365 or max")
+			precision := a.String("precision", "", "decimal places of prices")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			data, err := cg.GetCoinOHLCByCoinID(ctx, a.arg(0), *vs, *days, *precision)
+			if err != nil {
+				return nil, err
+			}
+			candles := make([]coingecko.OHLC, 0, len(*data))
+			for _, item := range *data {
+				candles = append(candles, item.OHLC())
+			}
+			return candles, nil
+		},
+	},
+	{
+		name:    "contract",
+		usage:   "<platform> <address>",
+		summary: "Get current data of a coin by token contract address.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetCoinInfoByContractAddress(ctx, a.arg(0), a.arg(1))
+		},
+	},
+	{
+		name:    "contract-chart",
+		usage:   "<platform> <address>",
+		summary: "Get market chart of a token contract address over the last days.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs := a.String("vs", "usd", "target currency")
+			days := a.String("days", "1", "number of days or max")
+			precision := a.String("precision", "", "decimal places of prices")
+			cg, err := coinGeckoArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetMarketChartByContractAddress(ctx, a.arg(0), a.arg(1), *vs, *days, *precision)
+		},
+	},
+	{
+		name:    "contract-chart-range",
+		usage:   "<platform> <address> --from <time> [--to <time>]",
+		summary: "Get market chart of a token contract address within a time range.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs := a.String("vs", "usd", "target currency")
+			from := a.String("from", "", "start time: unix seconds, RFC 3339 time or yyyy-mm-dd")
+			to := a.String("to", "", "end time, default: now")
+			precision := a.String("precision", "", "decimal places of prices")
+			cg, err := coinGeckoArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			start, end, err := parseRange(*from, *to)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetMarketChartRangeByContractAddressWithTime(ctx, a.arg(0), a.arg(1), *vs, start, end, *precision)
+		},
+	},
+	{
+		name:    "asset-platforms",
+		summary: "List all asset platforms.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			filter := a.String("filter", "", "filter, e.g. nft")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListAllAssetPlatforms(ctx, *filter)
+		},
+	},
+	{
+		name:    "categories",
+		summary: "List all coin categories.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			marketData := a.Bool("market-data", false, "include market data")
+			order := a.String("order", "", "sort order of market data, e.g. market_cap_desc")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			if *marketData {
+				return cg.ListAllCategoriesWithMarketData(ctx, *order)
+			}
+			return cg.ListAllCategories(ctx)
+		},
+	},
+	{
+		name:    "exchanges",
+		summary: "List exchanges with trading volume.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			perPage := a.Uint("per-page", 100, "results per page, 1 to 250")
+			page := a.Uint("page", 1, "page number")
+			all := a.Bool("all", false, "fetch all pages")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return pages(*all, *page, func(page uint) (*[]coingecko.ExchangesResponse, int, error) {
+				return cg.ListAllExchanges(ctx, *perPage, page)
+			})
+		},
+	},
+	{
+		name:    "exchanges-list",
+		summary: "List all exchange ids and names.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListAllMarketsInfo(ctx)
+		},
+	},
+	{
+		name:    "exchange",
+		usage:   "<id>",
+		summary: "Get volume and top 100 tickers of an exchange.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetExchangeVolumeAndTickersByExchangeID(ctx, a.arg(0))
+		},
+	},
+	{
+		name:    "exchange-tickers",
+		usage:   "<id>",
+		summary: "Get tickers of an exchange, 100 per page.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			coins := a.String("coins", "", "comma-separated coin ids")
+			logo := a.Bool("logo", false, "include exchange logos")
+			page := a.Uint("page", 1, "page number")
+			depth := a.Bool("depth", false, "include 2% order book depth")
+			order := a.String("order", "", "sort order: trust_score_desc, trust_score_asc or volume_desc")
+			all := a.Bool("all", false, "fetch all pages")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			fetch := func(page uint) (*coingecko.ExchangeTickersResponse, int, error) {
+				return cg.GetExchangeTickersByExchangeID(ctx, a.arg(0), *coins, *logo, page, *depth, *order)
+			}
+			if !*all {
+				data, _, err := fetch(*page)
+				return data, err
+			}
+			data, pageCount, err := fetch(1)
+			if err != nil {
+				return nil, err
+			}
+			data.Tickers, err = allTickers(data.Tickers, pageCount, func(page uint) ([]coingecko.TickersItem, error) {
+				data, _, err := fetch(page)
+				if err != nil {
+					return nil, err
+				}
+				return data.Tickers, nil
+			})
+			return data, err
+		},
+	},
+	{
+		name:    "exchange-volume",
+		usage:   "<id>",
+		summary: "Get volume chart in BTC of an exchange over the last days, or within a time range(paid plans).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			days := a.Uint("days", 1, "number of days: 1, 7, 14, 30, 90 or 180")
+			from := a.String("from", "", "start time of the range, up to 31 days")
+			to := a.String("to", "", "end time of the range, default: now")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			if *from == "" {
+				return cg.GetExchangeVolumeChartByExchangeID(ctx, a.arg(0), *days)
+			}
+			start, end, err := parseRange(*from, *to)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetVolumeChartRangeByExchangeIDWithTime(ctx, a.arg(0), start, end)
+		},
+	},
+	{
+		name:    "derivatives",
+		summary: "List all derivatives tickers.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			includeTickers := a.String("include-tickers", "", "all or unexpired")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListAllDerivativesTickers(ctx, *includeTickers)
+		},
+	},
+	{
+		name:    "derivatives-exchanges",
+		summary: "List derivatives exchanges with open interest and volume.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			order := a.String("order", "", "sort order, e.g. open_interest_btc_desc")
+			perPage := a.Uint("per-page", 50, "results per page")
+			page := a.Uint("page", 1, "page number")
+			all := a.Bool("all", false, "fetch all pages")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return pages(*all, *page, func(page uint) (*[]coingecko.DerivativesExchangesResponse, int, error) {
+				return cg.ListAllDerivativesExchanges(ctx, *order, *perPage, page)
+			})
+		},
+	},
+	{
+		name:    "derivatives-exchange",
+		usage:   "<id>",
+		summary: "Get data of a derivatives exchange.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			includeTickers := a.String("include-tickers", "", "all or unexpired")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListDerivativesExchangeData(ctx, a.arg(0), *includeTickers)
+		},
+	},
+	{
+		name:    "derivatives-exchanges-list",
+		summary: "List all derivatives exchange ids and names.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListAllDerivativeExchangeInfo(ctx)
+		},
+	},
+	{
+		name:    "nfts",
+		summary: "List NFT collections with id, contract address, name and symbol.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			order := a.String("order", "", "sort order, e.g. market_cap_usd_desc")
+			platform := a.String("platform", "", "asset platform id")
+			perPage := a.Uint("per-page", 100, "results per page, 1 to 250")
+			page := a.Uint("page", 1, "page number")
+			all := a.Bool("all", false, "fetch all pages")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return pages(*all, *page, func(page uint) (*[]coingecko.NFTInfoResponse, int, error) {
+				return cg.ListAllNFTInfo(ctx, *order, *platform, *perPage, page)
+			})
+		},
+	},
+	{
+		name:    "nft",
+		usage:   "<id> | <platform> <address>",
+		summary: "Get current data of an NFT collection by id or contract address.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 1, 2)
+			if err != nil {
+				return nil, err
+			}
+			if len(a.pos) == 2 {
+				return cg.GetNFTDataByAssetPlatformIDAndContractAddress(ctx, a.arg(0), a.arg(1))
+			}
+			return cg.GetNFTDataByNFTID(ctx, a.arg(0))
+		},
+	},
+	{
+		name:    "nft-markets",
+		summary: "List NFT collections with floor price, market cap and volume(paid plans).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			platform := a.String("platform", "", "asset platform id")
+			order := a.String("order", "", "sort order, e.g. market_cap_usd_desc")
+			perPage := a.Uint("per-page", 100, "results per page, 1 to 250")
+			page := a.Uint("page", 1, "page number")
+			all := a.Bool("all", false, "fetch all pages")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return pages(*all, *page, func(page uint) (*[]coingecko.NFTsMarketsResponse, int, error) {
+				return cg.ListAllNFTsMarketsData(ctx, *platform, *order, *perPage, page)
+			})
+		},
+	},
+	{
+		name:    "nft-chart",
+		usage:   "<id> | <platform> <address>",
+		summary: "Get floor price, market cap and volume chart of an NFT collection(paid plans).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			days := a.String("days", "1", "number of days or max")
+			cg, err := coinGeckoArgs(c, a, 1, 2)
+			if err != nil {
+				return nil, err
+			}
+			if len(a.pos) == 2 {
+				return cg.GetMarketChartByNFTContractAddress(ctx, a.arg(0), a.arg(1), *days)
+			}
+			return cg.GetMarketChartByNFTID(ctx, a.arg(0), *days)
+		},
+	},
+	{
+		name:    "nft-tickers",
+		usage:   "<id>",
+		summary: "Get floor price and volume of an NFT collection on each marketplace(paid plans).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetNFTTickersByNFTID(ctx, a.arg(0))
+		},
+	},
+	{
+		name:    "exchange-rates",
+		summary: "Get BTC exchange rates of fiat and crypto currencies.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetExchangeRates(ctx)
+		},
+	},
+	{
+		name:    "search",
+		usage:   "<query>",
+		summary: "Search coins, exchanges, categories and NFTs.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			kind := a.String("type", "coins", "result type: coins, exchanges, categories, nfts or all")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			data, err := cg.Search(ctx, a.arg(0))
+			if err != nil {
+				return nil, err
+			}
+			switch *kind {
+			case "coins":
+				return data.Coins, nil
+			case "exchanges":
+				return data.Exchanges, nil
+			case "categories":
+				return data.Categories, nil
+			case "nfts":
+				return data.NFTs, nil
+			case "all":
+				return data, nil
+			default:
+				return nil, fmt.Errorf("invalid type %q, valid values: coins, exchanges, categories, nfts, all", *kind)
+			}
+		},
+	},
+	{
+		name:    "trending",
+		summary: "Get trending coins and NFTs in the last 24 hours.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			kind := a.String("type", "coins", "result type: coins, nfts or all")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			data, err := cg.SearchTrending(ctx)
+			if err != nil {
+				return nil, err
+			}
+			switch *kind {
+			case "coins":
+				coins := make([]coingecko.SearchTrendingCoinItem, 0, len(data.Coins))
+				for _, coin := range data.Coins {
+					coins = append(coins, coin.SearchTrendingCoinItem)
+				}
+				return coins, nil
+			case "nfts":
+				nfts := make([]coingecko.SearchTrendingNFTItem, 0, len(data.NFTs))
+				for _, nft := range data.NFTs {
+					nfts = append(nfts, nft.SearchTrendingNFTItem)
+				}
+				return nfts, nil
+			case "all":
+				return data, nil
+			default:
+				return nil, fmt.Errorf("invalid type %q, valid values: coins, nfts, all", *kind)
+			}
+		},
+	},
+	{
+		name:    "global",
+		summary: "Get global cryptocurrency market data.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetGlobalCryptocurrencyData(ctx)
+		},
+	},
+	{
+		name:    "global-defi",
+		summary: "Get global DeFi market data.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetGlobalTop100DefiData(ctx)
+		},
+	},
+	{
+		name:    "global-chart",
+		summary: "Get global market cap and volume chart over the last days(paid plans).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			days := a.String("days", "1", "number of days or max")
+			vs := a.String("vs", "usd", "target currency")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetGlobalMarketCapChartData(ctx, *days, *vs)
+		},
+	},
+	{
+		name:    "treasury",
+		usage:   "<coin>",
+		summary: "Get public companies holding bitcoin or ethereum.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetCompaniesPublicTreasury(ctx, a.arg(0))
+		},
+	},
+	{
+		name:    "new-coins",
+		summary: "List the latest 200 coins listed on CoinGecko(paid plans).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListLatest200Coins(ctx)
+		},
+	},
+	{
+		name:    "gainers-losers",
+		summary: "Get top 30 gainers and losers(paid plans).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			vs := a.String("vs", "usd", "target currency")
+			duration := a.String("duration", "", "price change duration, e.g. 1h, 24h or 7d")
+			topCoins := a.String("top-coins", "", "filter by market cap ranking, e.g. 300 or all")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetTopGainersLosers(ctx, *vs, *duration, *topCoins)
+		},
+	},
+	{
+		name:    "supply-chart",
+		usage:   "<id>",
+		summary: "Get circulating supply chart of a coin over the last days or within a time range(enterprise plan).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			days := a.Uint("days", 1, "number of days")
+			interval := a.String("interval", "", "data interval, e.g. daily")
+			from := a.String("from", "", "start time of the range")
+			to := a.String("to", "", "end time of the range, default: now")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			if *from == "" {
+				return cg.GetCirculatingSupplyChartByCoinID(ctx, a.arg(0), *days, *interval)
+			}
+			start, end, err := parseRange(*from, *to)
+			if err != nil {
+				return nil, err
+			}
+			return cg.GetCirculatingSupplyChartRangeByCoinIDWithTime(ctx, a.arg(0), start, end)
+		},
+	},
+	{
+		name:    "tokens-list",
+		usage:   "<platform>",
+		summary: "Get the token list of an asset platform(enterprise plan).",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return cg.ListAllTokensByAssetPlatformID(ctx, a.arg(0))
+		},
+	},
+}
+
+// priceFlags defines the flags shared by price and token-price commands.
+func priceFlags(a *args) (vs *string, marketCap, volume, change, updatedAt *bool, precision *string) {
+	vs = a.String("vs", "usd", "comma-separated target currencies")
+	marketCap = a.Bool("market-cap", false, "include market cap")
+	volume = a.Bool("volume", false, "include 24h volume")
+	change = a.Bool("change", false, "include 24h change")
+	updatedAt = a.Bool("updated-at", false, "include last updated time")
+	precision = a.String("precision", "", "decimal places of prices")
+	return vs, marketCap, volume, change, updatedAt, precision
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+// config is the content of the config file, environment variables and flags override it.
+type config struct {
+	APIKey                 string `json:"api_key"`
+	Pro                    bool   `json:"pro"`
+	RateLimit              *int   `json:"rate_limit"`
+	GeckoTerminalRateLimit *int   `json:"geckoterminal_rate_limit"`
+	Output                 string `json:"output"`
+}
+
+// loadConfig loads the config file at path. If path is empty, the CGCLI_CONFIG environment variable or the default
+// path is used, and a missing file is not an error.
+func loadConfig(path string) (*config, error) {
+	var cfg config
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv("CGCLI_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return &cfg, nil
+		}
+		path = filepath.Join(dir, "cgcli", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return &cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// clients creates the API clients on first use, after the global flags of the command are parsed.
+type clients struct {
+	globals *globals
+	baseURL string
+	stdout  io.Writer
+	stderr  io.Writer
+
+	cfg *config
+	cg  *coingecko.Client
+	gt  *geckoterminal.Client
+}
+
+// config returns the merged config: the config file, then environment variables, then flags.
+func (c *clients) config() (*config, error) {
+	if c.cfg != nil {
+		return c.cfg, nil
+	}
+	cfg, err := loadConfig(c.globals.configPath)
+	if err != nil {
+		return nil, err
+	}
+	if key := os.Getenv("COINGECKO_API_KEY"); key != "" {
+		cfg.APIKey = key
+	}
+	if pro := os.Getenv("COINGECKO_PRO"); pro != "" {
+		if cfg.Pro, err = strconv.ParseBool(pro); err != nil {
+			return nil, fmt.Errorf("invalid COINGECKO_PRO: %w", err)
+		}
+	}
+	if c.globals.rateLimit.set {
+		cfg.RateLimit = &c.globals.rateLimit.value
+	}
+	if c.globals.geckoTerminalRateLimit.set {
+		cfg.GeckoTerminalRateLimit = &c.globals.geckoTerminalRateLimit.value
+	}
+	if c.globals.output != "" {
+		cfg.Output = c.globals.output
+	}
+	if cfg.Output == "" {
+		cfg.Output = formatTable
+	}
+	c.cfg = cfg
+	return cfg, nil
+}
+
+// coingecko returns the CoinGecko client. Public and demo keys are limited to defaultRateLimit calls per minute
+// unless configured, Pro keys are unlimited.
+func (c *clients) coingecko() (*coingecko.Client, error) {
+	if c.cg != nil {
+		return c.cg, nil
+	}
+	cfg, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+	limit := 0
+	if !cfg.Pro {
+		limit = defaultRateLimit
+	}
+	if cfg.RateLimit != nil {
+		limit = *cfg.RateLimit
+	}
+	var opts []coingecko.Option
+	if limit > 0 {
+		opts = append(opts, coingecko.WithRateLimit(limit))
+	}
+	if c.baseURL != "" {
+		opts = append(opts, coingecko.WithBaseURL(c.baseURL+"/api/v3"))
+	}
+	c.cg = coingecko.NewCoinGecko(cfg.APIKey, cfg.Pro, nil, opts...)
+	setLogger(c.stderr, c.globals.verbose)
+	return c.cg, nil
+}
+
+// geckoterminal returns the GeckoTerminal client, limited to defaultGeckoTerminalRateLimit calls per minute unless
+// configured.
+func (c *clients) geckoterminal() (*geckoterminal.Client, error) {
+	if c.gt != nil {
+		return c.gt, nil
+	}
+	cfg, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+	limit := defaultGeckoTerminalRateLimit
+	if cfg.GeckoTerminalRateLimit != nil {
+		limit = *cfg.GeckoTerminalRateLimit
+	}
+	var opts []geckoterminal.Option
+	if limit > 0 {
+		opts = append(opts, geckoterminal.WithRateLimit(limit))
+	}
+	if c.baseURL != "" {
+		opts = append(opts, geckoterminal.WithBaseURL(c.baseURL+"/api/v2"))
+	}
+	c.gt = geckoterminal.NewGeckoTerminal(nil, opts...)
+	setLogger(c.stderr, c.globals.verbose)
+	return c.gt, nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bufdata/coingecko-api/export"
+)
+
+// exportDataset is a dataset of the export command. run defines the flags of the dataset, parses a, fetches the data
+// and writes it with out.
+type exportDataset struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, c *clients, a *args, out *exportOutput) error
+}
+
+var exportDatasets = []exportDataset{
+	{
+		name:    "market-chart",
+		usage:   "<id> --from <time> [--to <time>]",
+		summary: "Price, market cap and volume of a coin within a time range.",
+		run: func(ctx context.Context, c *clients, a *args, out *exportOutput) error {
+			vs := a.String("vs", "usd", "target currency")
+			from := a.String("from", "", "start time: unix seconds, RFC 3339 time or yyyy-mm-dd")
+			to := a.String("to", "", "end time, default: now")
+			granularity := a.String("granularity", "", "data interval: 5-minutely, hourly or daily, "+
+				"long ranges are fetched in chunks")
+			precision := a.String("precision", "", "decimal places of prices")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return err
+			}
+			data, err := marketChartRange(ctx, cg, a.arg(0), *vs, *from, *to, *granularity, *precision)
+			if err != nil {
+				return err
+			}
+			return out.write(export.MarketChartColumns, func(w export.Writer) error {
+				return export.WriteMarketChart(w, a.arg(0), *vs, data)
+			})
+		},
+	},
+	{
+		name:    "ohlc",
+		usage:   "<id>",
+		summary: "OHLC candles of a coin over the last days.",
+		run: func(ctx context.Context, c *clients, a *args, out *exportOutput) error {
+			vs := a.String("vs", "usd", "target currency")
+			days := a.String("days", "1", "number of days: 1, 7, 14, 30, 90, 180, 365 or max")
+			precision := a.String("precision", "", "decimal places of prices")
+			cg, err := coinGeckoArgs(c, a, 1, 1)
+			if err != nil {
+				return err
+			}
+			data, err := cg.GetCoinOHLCByCoinID(ctx, a.arg(0), *vs, *days, *precision)
+			if err != nil {
+				return err
+			}
+			return out.write(export.OHLCColumns, func(w export.Writer) error {
+				return export.WriteOHLC(w, a.arg(0), *vs, *data)
+			})
+		},
+	},
+	{
+		name:    "ohlcv",
+		usage:   "<network> <pool>",
+		summary: "OHLCV candles of a pool, --since and --all walk back through older pages.",
+		run: func(ctx context.Context, c *clients, a *args, out *exportOutput) error {
+			f := defineOHLCVFlags(a)
+			gt, err := geckoTerminalArgs(c, a, 2, 2)
+			if err != nil {
+				return err
+			}
+			items, err := f.fetch(ctx, gt, a.arg(0), a.arg(1))
+			if err != nil {
+				return err
+			}
+			// a single page is newest first.
+			sort.SliceStable(items, func(i, j int) bool { return items[i][0] < items[j][0] })
+			source := export.OHLCVSource{Network: a.arg(0), PoolAddress: a.arg(1), Timeframe: *f.timeframe,
+				Aggregate: *f.aggregate, Currency: *f.currency}
+			return out.write(export.OHLCVColumns, func(w export.Writer) error {
+				return export.WriteOHLCV(w, source, items)
+			})
+		},
+	},
+	{
+		name:    "markets",
+		summary: "Snapshot of coins with price, market cap and volume, pages are written as they are fetched.",
+		run: func(ctx context.Context, c *clients, a *args, out *exportOutput) error {
+			vs := a.String("vs", "usd", "target currency")
+			ids := a.String("ids", "", "comma-separated coin ids")
+			category := a.String("category", "", "coin category")
+			order := a.String("order", "", "sort order, e.g. market_cap_desc or volume_desc")
+			perPage := a.Uint("per-page", 250, "results per page, 1 to 250")
+			pages := a.Uint("pages", 1, "number of pages")
+			cg, err := coinGeckoArgs(c, a, 0, 0)
+			if err != nil {
+				return err
+			}
+			snapshot := time.Now().UTC()
+			return out.write(export.MarketsColumns, func(w export.Writer) error {
+				for page := uint(1); page <= *pages; page++ {
+					data, err := cg.ListCoinsMarketsData(ctx, *vs, splitList(*ids), *category, *order, *perPage, page,
+						false, nil, "", "")
+					if err != nil {
+						return err
+					}
+					if err = export.WriteMarkets(w, snapshot, *vs, *data); err != nil {
+						return err
+					}
+					if uint(len(*data)) < *perPage {
+						return nil
+					}
+				}
+				return nil
+			})
+		},
+	},
+}
+
+var exportCommand = command{
+	name:    "export",
+	usage:   "<dataset> [arguments] [--format csv|jsonl|parquet] [--out <path>]",
+	summary: exportSummary(),
+	run:     runExport,
+}
+
+// exportSummary lists the datasets in the summary of the export command.
+func exportSummary() string {
+	var b strings.Builder
+	b.WriteString("Export historical data as CSV, JSON Lines or Parquet tables.\n\ndatasets:")
+	for _, d := range exportDatasets {
+		fmt.Fprintf(&b, "\n  %-12s  %s", d.name, d.summary)
+	}
+	b.WriteString("\n\nRun `cgcli export <dataset> -h` for the flags of a dataset.")
+	return b.String()
+}
+
+func runExport(ctx context.Context, c *clients, a *args) (any, error) {
+	out := &exportOutput{
+		stdout: c.stdout,
+		format: a.String("format", "", "output format: csv, jsonl or parquet, default: the extension of --out or csv"),
+		path:   a.String("out", "", "output file, default: stdout"),
+	}
+	var d *exportDataset
+	if len(a.raw) > 0 {
+		for i := range exportDatasets {
+			if exportDatasets[i].name == a.raw[0] {
+				d = &exportDatasets[i]
+			}
+		}
+	}
+	if d == nil {
+		if err := a.parse(0, len(a.raw)); err != nil {
+			return nil, err
+		}
+		a.Usage()
+		names := make([]string, 0, len(exportDatasets))
+		for _, dataset := range exportDatasets {
+			names = append(names, dataset.name)
+		}
+		return nil, fmt.Errorf("export: expected dataset: %s", strings.Join(names, ", "))
+	}
+
+	a.raw = a.raw[1:]
+	a.Usage = func() {
+		fmt.Fprintf(c.stderr, "usage: cgcli export %s %s\n\n%s\n\nflags:\n", d.name, d.usage, d.summary)
+		a.PrintDefaults()
+	}
+	return nil, d.run(ctx, c, a, out)
+}
+
+// exportOutput is the destination of the export command: the file of --out or stdout, in the format of --format.
+type exportOutput struct {
+	stdout io.Writer
+	format *string
+	path   *string
+}
+
+// write calls fn with a writer of columns and closes it. The file is removed if fn fails, so that no partial export
+// is left.
+func (o *exportOutput) write(columns []export.Column, fn func(w export.Writer) error) (err error) {
+	format := export.Format(*o.format)
+	if format == "" {
+		format = formatOfPath(*o.path)
+	}
+	buf := bufio.NewWriter(o.stdout)
+	w, err := export.NewWriter(buf, format, columns)
+	if err != nil {
+		return err
+	}
+	if *o.path != "" {
+		var f *os.File
+		if f, err = os.Create(*o.path); err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(*o.path)
+			}
+		}()
+		buf.Reset(f)
+	}
+
+	if err = fn(w); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// formatOfPath returns the format of the extension of path, csv by default.
+func formatOfPath(path string) export.Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		return export.JSONL
+	case ".parquet":
+		return export.Parquet
+	default:
+		return export.CSV
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufdata/coingecko-api/export"
+)
+
+func TestExportOutput(t *testing.T) {
+	dir := t.TempDir()
+	columns := []export.Column{{Name: "coin_id", Type: export.String}}
+	writeRow := func(w export.Writer) error { return w.Write([]any{"bitcoin"}) }
+
+	path := filepath.Join(dir, "coins.parquet")
+	format := ""
+	out := &exportOutput{stdout: &bytes.Buffer{}, format: &format, path: &path}
+	if err := out.write(columns, writeRow); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatalf("file should be parquet, got: %q", data)
+	}
+
+	path = filepath.Join(dir, "failed.csv")
+	if err = out.write(columns, func(export.Writer) error { return errors.New("api error") }); err == nil {
+		t.Fatal("error should not be nil")
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("failed export should be removed, got: %v", err)
+	}
+
+	format = "xlsx"
+	if err = out.write(columns, writeRow); err == nil {
+		t.Fatal("invalid format should fail")
+	}
+}
+
+func TestFormatOfPath(t *testing.T) {
+	cases := map[string]export.Format{
+		"":             export.CSV,
+		"out.csv":      export.CSV,
+		"out.JSONL":    export.JSONL,
+		"out.ndjson":   export.JSONL,
+		"data.parquet": export.Parquet,
+		"data.txt":     export.CSV,
+	}
+	for path, wanted := range cases {
+		if got := formatOfPath(path); got != wanted {
+			t.Fatalf("incorrect format of %q, wanted: %s, got: %s", path, wanted, got)
+		}
+	}
+}
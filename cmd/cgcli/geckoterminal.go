@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+// geckoTerminalArgs parses a and returns the GeckoTerminal client.
+func geckoTerminalArgs(c *clients, a *args, minArgs, maxArgs int) (*geckoterminal.Client, error) {
+	if err := a.parse(minArgs, maxArgs); err != nil {
+		return nil, err
+	}
+	return c.geckoterminal()
+}
+
+// includeFlag defines the include flag of pool and token commands.
+func includeFlag(a *args, values string) *string {
+	return a.String("include", "", "comma-separated related resources to include: "+values)
+}
+
+// linkedPages fetches page, or all pages by following the next links if all is set.
+func linkedPages[T any](all bool, page uint, fetch func(page uint) ([]T, *string, error)) (any, error) {
+	if !all {
+		items, _, err := fetch(page)
+		return items, err
+	}
+	var items []T
+	for page = 1; ; page++ {
+		data, next, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, data...)
+		if len(data) == 0 || next == nil {
+			return items, nil
+		}
+	}
+}
+
+func ohlcvCandles(items []geckoterminal.OHLCVItem) []geckoterminal.OHLCV {
+	candles := make([]geckoterminal.OHLCV, 0, len(items))
+	for _, item := range items {
+		candles = append(candles, item.OHLCV())
+	}
+	return candles
+}
+
+// ohlcvFlags are the flags of the ohlcv command and of the ohlcv dataset of the export command.
+type ohlcvFlags struct {
+	timeframe, before, currency, token, since *string
+	aggregate, limit                          *uint
+	all                                       *bool
+}
+
+// defineOHLCVFlags defines the flags of OHLCV candles on a.
+func defineOHLCVFlags(a *args) *ohlcvFlags {
+	return &ohlcvFlags{
+		timeframe: a.String("timeframe", "day", "timeframe: day, hour or minute"),
+		aggregate: a.Uint("aggregate", 1, "candle size in timeframes: 1 for day, 1, 4 or 12 for hour, "+
+			"1, 5 or 15 for minute"),
+		before:   a.String("before", "", "return candles before this time, default: now"),
+		limit:    a.Uint("limit", 100, "number of candles, up to 1000, ignored by --since and --all"),
+		currency: a.String("currency", "", "usd or token"),
+		token:    a.String("token", "", "base or quote"),
+		since:    a.String("since", "", "walk back to this time"),
+		all:      a.Bool("all", false, "walk back to the creation of the pool"),
+	}
+}
+
+// fetch returns the candles of a pool: a single page, or the pages back to --since or the creation of the pool in
+// chronological order.
+func (f *ohlcvFlags) fetch(ctx context.Context, gt *geckoterminal.Client, network, pool string) (
+	[]geckoterminal.OHLCVItem, error) {
+	var (
+		beforeTime time.Time
+		err        error
+	)
+	if *f.before != "" {
+		if beforeTime, err = parseTime(*f.before); err != nil {
+			return nil, err
+		}
+	}
+	if *f.since == "" && !*f.all {
+		var beforeTimestamp int64
+		if !beforeTime.IsZero() {
+			beforeTimestamp = beforeTime.Unix()
+		}
+		data, err := gt.GetOHLCV(ctx, network, pool, *f.timeframe, *f.aggregate, beforeTimestamp, *f.limit,
+			*f.currency, *f.token)
+		if err != nil {
+			return nil, err
+		}
+		return data.Data.Attributes.OHLCVList, nil
+	}
+
+	opts := geckoterminal.OHLCVBackfillOptions{Aggregate: *f.aggregate, Currency: *f.currency, Token: *f.token,
+		Before: beforeTime}
+	if *f.since != "" {
+		if opts.Start, err = parseTime(*f.since); err != nil {
+			return nil, err
+		}
+	}
+	if *f.timeframe != "day" && *f.timeframe != "hour" && *f.timeframe != "minute" {
+		return nil, fmt.Errorf("invalid timeframe %q, valid values: day, hour, minute", *f.timeframe)
+	}
+	return gt.NewOHLCVBackfill(network, pool, *f.timeframe, opts).All(ctx)
+}
+
+var geckoTerminalCommands = []command{
+	{
+		name:    "networks",
+		summary: "List GeckoTerminal networks.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			page := a.Uint("page", 1, "page number")
+			all := a.Bool("all", false, "fetch all pages")
+			gt, err := geckoTerminalArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return linkedPages(*all, *page, func(page uint) ([]geckoterminal.NetworksItem, *string, error) {
+				data, err := gt.GetNetworks(ctx, page)
+				if err != nil {
+					return nil, nil, err
+				}
+				items := make([]geckoterminal.NetworksItem, 0, len(data.Data))
+				for _, item := range data.Data {
+					items = append(items, item.NetworksItem)
+				}
+				return items, data.Next, nil
+			})
+		},
+	},
+	{
+		name:    "dexes",
+		usage:   "<network>",
+		summary: "List dexes of a network.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			page := a.Uint("page", 1, "page number")
+			all := a.Bool("all", false, "fetch all pages")
+			gt, err := geckoTerminalArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return linkedPages(*all, *page, func(page uint) ([]geckoterminal.DexesItem, *string, error) {
+				data, err := gt.GetDexes(ctx, a.arg(0), page)
+				if err != nil {
+					return nil, nil, err
+				}
+				items := make([]geckoterminal.DexesItem, 0, len(data.Data))
+				for _, item := range data.Data {
+					items = append(items, item.DexesItem)
+				}
+				return items, data.Next, nil
+			})
+		},
+	},
+	{
+		name:    "pool",
+		usage:   "<network> <addresses>",
+		summary: "Get pools by comma-separated addresses, up to 30.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			include := includeFlag(a, "base_token, quote_token, dex")
+			gt, err := geckoTerminalArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			if addresses := a.list(1); len(addresses) > 1 {
+				return gt.GetMultiPools(ctx, a.arg(0), splitList(*include), addresses)
+			}
+			return gt.GetSpecificPool(ctx, a.arg(0), a.arg(1), splitList(*include))
+		},
+	},
+	{
+		name:    "top-pools",
+		usage:   "<network> [dex]",
+		summary: "Get top 20 pools of a network or a dex.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			include := includeFlag(a, "base_token, quote_token, dex")
+			gt, err := geckoTerminalArgs(c, a, 1, 2)
+			if err != nil {
+				return nil, err
+			}
+			if len(a.pos) == 2 {
+				return gt.GetTop20PoolsOnOneDex(ctx, a.arg(0), a.arg(1), splitList(*include))
+			}
+			return gt.GetTop20PoolsOnOneNetwork(ctx, a.arg(0), splitList(*include))
+		},
+	},
+	{
+		name:    "new-pools",
+		usage:   "[network]",
+		summary: "Get the latest 20 pools of a network or all networks.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			include := includeFlag(a, "base_token, quote_token, dex, network")
+			gt, err := geckoTerminalArgs(c, a, 0, 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(a.pos) == 1 {
+				return gt.GetLatest20PoolsOnOneNetwork(ctx, a.arg(0), splitList(*include))
+			}
+			return gt.GetLatest20PoolsOnAllNetworks(ctx, splitList(*include))
+		},
+	},
+	{
+		name:    "search-pools",
+		usage:   "<query>",
+		summary: "Search pools by pool address, token address or token symbol.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			network := a.String("network", "", "network id")
+			include := includeFlag(a, "base_token, quote_token, dex")
+			gt, err := geckoTerminalArgs(c, a, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return gt.SearchPools(ctx, a.arg(0), *network, splitList(*include))
+		},
+	},
+	{
+		name:    "token-pools",
+		usage:   "<network> <token>",
+		summary: "Get top 20 pools of a token.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			include := includeFlag(a, "base_token, quote_token, dex")
+			gt, err := geckoTerminalArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			return gt.GetTop20PoolsForOneToken(ctx, a.arg(0), a.arg(1), splitList(*include))
+		},
+	},
+	{
+		name:    "token",
+		usage:   "<network> <addresses>",
+		summary: "Get tokens by comma-separated addresses, up to 30.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			include := includeFlag(a, "top_pools")
+			gt, err := geckoTerminalArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			if addresses := a.list(1); len(addresses) > 1 {
+				return gt.GetMultiTokensOnOneNetwork(ctx, a.arg(0), addresses, splitList(*include))
+			}
+			return gt.GetSpecificTokenOnOneNetwork(ctx, a.arg(0), a.arg(1), splitList(*include))
+		},
+	},
+	{
+		name:    "token-info",
+		usage:   "<network> <address>",
+		summary: "Get metadata of a token: socials, websites and description.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			gt, err := geckoTerminalArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			return gt.GetSpecificTokenInfoOnOneNetwork(ctx, a.arg(0), a.arg(1))
+		},
+	},
+	{
+		name:    "pool-info",
+		usage:   "<network> <pool>",
+		summary: "Get metadata of the tokens of a pool.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			gt, err := geckoTerminalArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			return gt.GetPoolTokensInfoOnOneNetwork(ctx, a.arg(0), a.arg(1))
+		},
+	},
+	{
+		name:    "recent-tokens",
+		summary: "Get the 100 most recently updated tokens info.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			include := includeFlag(a, "network")
+			gt, err := geckoTerminalArgs(c, a, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			return gt.GetRecentlyUpdated100TokensInfo(ctx, splitList(*include))
+		},
+	},
+	{
+		name:    "ohlcv",
+		usage:   "<network> <pool>",
+		summary: "Get OHLCV candles of a pool, --since and --all walk back through older pages.",
+		run: func(ctx context.Context, c *clients, a *args) (any, error) {
+			f := defineOHLCVFlags(a)
+			gt, err := geckoTerminalArgs(c, a, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			items, err := f.fetch(ctx, gt, a.arg(0), a.arg(1))
+			if err != nil {
+				return nil, err
+			}
+			return ohlcvCandles(items), nil
+		},
+	},
+}
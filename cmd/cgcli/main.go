@@ -0,0 +1,193 @@
+// Command cgcli looks up CoinGecko and GeckoTerminal data from the command line.
+//
+// Usage:
+//
+//	cgcli [global flags] <command> [arguments] [flags]
+//
+// For example:
+//
+//	cgcli price bitcoin,ethereum --vs usd,eur
+//	cgcli pool eth 0x60594a405d53811d3bc4766596efd80fd545a270
+//	cgcli ohlcv eth 0x60594a405d53811d3bc4766596efd80fd545a270 --timeframe hour --aggregate 4 -o csv
+//	cgcli export market-chart bitcoin --from 2023-01-01 --granularity hourly --out bitcoin.parquet
+//
+// Run `cgcli help` for the list of commands and `cgcli help <command>` for the flags of a command.
+//
+// The CoinGecko API key is read from the COINGECKO_API_KEY environment variable or the api_key field of the config
+// file, set COINGECKO_PRO=true or the pro field for Pro API keys. The config file is a JSON file at the path of the
+// --config flag or the CGCLI_CONFIG environment variable, by default cgcli/config.json in the user config directory:
+//
+//	{"api_key": "...", "pro": true, "rate_limit": 500, "geckoterminal_rate_limit": 30, "output": "table"}
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+)
+
+const (
+	// defaultRateLimit is the rate limit of public and demo API keys in calls per minute.
+	defaultRateLimit = 30
+	// defaultGeckoTerminalRateLimit is the rate limit of GeckoTerminal API in calls per minute.
+	defaultGeckoTerminalRateLimit = 30
+)
+
+// command is a cgcli subcommand. run defines the flags of the command, parses a and calls the API.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, c *clients, a *args) (any, error)
+}
+
+// commands is the list of subcommands.
+var commands = append(append(append([]command(nil), coinGeckoCommands...), geckoTerminalCommands...), exportCommand)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr, ""))
+}
+
+// run runs cgcli with arguments and returns the exit code. baseURL is the URL of a server serving both APIs under
+// /api/v3 and /api/v2 like cmd/cgproxy, empty means the public APIs.
+func run(ctx context.Context, arguments []string, stdout, stderr io.Writer, baseURL string) int {
+	g := &globals{}
+	fs := flag.NewFlagSet("cgcli", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { printUsage(stderr) }
+	g.register(fs)
+	if err := fs.Parse(arguments); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 {
+		printUsage(stderr)
+		return 2
+	}
+	name, rest := rest[0], rest[1:]
+	if name == "help" {
+		if len(rest) == 0 {
+			printUsage(stdout)
+			return 0
+		}
+		name, rest = rest[0], []string{"-h"}
+	}
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(stderr, "cgcli: unknown command %q, run `cgcli help` for the list of commands\n", name)
+		return 2
+	}
+
+	a := &args{FlagSet: flag.NewFlagSet(cmd.name, flag.ContinueOnError), raw: rest}
+	a.SetOutput(stderr)
+	a.Usage = func() {
+		fmt.Fprintf(stderr, "usage: cgcli %s %s\n\n%s\n\nflags:\n", cmd.name, cmd.usage, cmd.summary)
+		a.PrintDefaults()
+	}
+	g.register(a.FlagSet)
+
+	c := &clients{globals: g, baseURL: baseURL, stdout: stdout, stderr: stderr}
+	result, err := cmd.run(ctx, c, a)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(stderr, "cgcli: %v\n", err)
+		return 1
+	}
+	if result == nil {
+		// the command wrote its own output, e.g. export.
+		return 0
+	}
+
+	cfg, err := c.config()
+	if err != nil {
+		fmt.Fprintf(stderr, "cgcli: %v\n", err)
+		return 1
+	}
+	if err = write(stdout, cfg.Output, result, splitList(g.columns)); err != nil {
+		fmt.Fprintf(stderr, "cgcli: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, `cgcli looks up CoinGecko and GeckoTerminal data.
+
+usage: cgcli [global flags] <command> [arguments] [flags]
+
+global flags, also accepted after the command:
+  -o, --output string   output format: table, json or csv (default table)
+  --columns string      comma-separated columns of table and csv output
+  --config string       path of the config file
+  --rate-limit int      CoinGecko calls per minute, 0 means unlimited
+  --gt-rate-limit int   GeckoTerminal calls per minute, 0 means unlimited
+  -v, --verbose         log API errors to stderr
+
+commands:
+`)
+	sorted := append([]command(nil), commands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	width := 0
+	for _, cmd := range sorted {
+		width = max(width, len(cmd.name))
+	}
+	for _, cmd := range sorted {
+		summary, _, _ := strings.Cut(cmd.summary, "\n")
+		fmt.Fprintf(w, "  %-*s  %s\n", width, cmd.name, summary)
+	}
+}
+
+// globals are the flags accepted before and after the command.
+type globals struct {
+	output                 string
+	columns                string
+	configPath             string
+	rateLimit              optionalInt
+	geckoTerminalRateLimit optionalInt
+	verbose                bool
+}
+
+func (g *globals) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.output, "o", g.output, "output format: table, json or csv")
+	fs.StringVar(&g.output, "output", g.output, "output format: table, json or csv")
+	fs.StringVar(&g.columns, "columns", g.columns, "comma-separated columns of table and csv output")
+	fs.StringVar(&g.configPath, "config", g.configPath, "path of the config file")
+	fs.Var(&g.rateLimit, "rate-limit", "CoinGecko `calls` per minute, 0 means unlimited")
+	fs.Var(&g.geckoTerminalRateLimit, "gt-rate-limit", "GeckoTerminal `calls` per minute, 0 means unlimited")
+	fs.BoolVar(&g.verbose, "v", g.verbose, "log API errors to stderr")
+	fs.BoolVar(&g.verbose, "verbose", g.verbose, "log API errors to stderr")
+}
+
+// setLogger replaces the default logger set by the API clients, which writes to stdout and would mix with the
+// output.
+func setLogger(w io.Writer, verbose bool) {
+	level := slog.LevelError + 1
+	if verbose {
+		level = slog.LevelDebug
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})))
+}
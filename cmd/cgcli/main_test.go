@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockServer returns the URL of a mock server of both APIs.
+func mockServer(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CGCLI_CONFIG", path)
+	t.Setenv("COINGECKO_API_KEY", "")
+	var requests []string
+	baseURL := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/simple/price"):
+			_, _ = w.Write([]byte(`{"bitcoin":{"usd":42000,"eur":39000},"ethereum":{"usd":2200,"eur":2050}}`))
+		case strings.Contains(r.URL.Path, "/ohlcv/"):
+			_, _ = w.Write([]byte(`{"data":{"id":"x","type":"ohlcv_request_response","attributes":{"ohlcv_list":
+[[1698796800,1,2,0.5,1.5,100]]}}}`))
+		case strings.HasSuffix(r.URL.Path, "/exchanges"):
+			w.Header().Add("total", "3")
+			_, _ = w.Write([]byte(`[{"id":"binance","name":"Binance"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+		}
+	})
+
+	cases := []struct {
+		name           string
+		args           []string
+		wantedCode     int
+		wantedOutput   string
+		wantedRequests []string
+		wantedStderr   string
+	}{
+		{
+			name:           "price with flags after arguments",
+			args:           []string{"price", "bitcoin,ethereum", "--vs", "usd,eur", "-o", "csv", "--columns", "id,usd"},
+			wantedOutput:   "id,usd\nbitcoin,42000\nethereum,2200\n",
+			wantedRequests: []string{"/api/v3/simple/price?ids=bitcoin%2Cethereum&vs_currencies=usd%2Ceur"},
+		},
+		{
+			name:         "ohlcv",
+			args:         []string{"--gt-rate-limit", "0", "ohlcv", "eth", "0xpool", "--timeframe", "hour", "--aggregate", "4"},
+			wantedOutput: "TIME                  OPEN  HIGH  LOW  CLOSE  VOLUME\n2023-11-01T00:00:00Z  1     2     0.5  1.5    100\n",
+			wantedRequests: []string{
+				"/api/v2/networks/eth/pools/0xpool/ohlcv/hour?aggregate=4&currency=usd&limit=100"},
+		},
+		{
+			name:         "all pages",
+			args:         []string{"exchanges", "--all", "--per-page", "1", "--rate-limit", "0", "-o", "csv", "--columns", "id"},
+			wantedOutput: "id\nbinance\nbinance\nbinance\n",
+			wantedRequests: []string{"/api/v3/exchanges?page=1&per_page=1", "/api/v3/exchanges?page=2&per_page=1",
+				"/api/v3/exchanges?page=3&per_page=1"},
+		},
+		{
+			name: "export",
+			args: []string{"--gt-rate-limit", "0", "export", "ohlcv", "eth", "0xpool", "--timeframe", "hour",
+				"--aggregate", "4"},
+			wantedOutput: "timestamp,network,pool_address,timeframe,aggregate,currency,open,high,low,close,volume\n" +
+				"2023-11-01T00:00:00Z,eth,0xpool,hour,4,usd,1,2,0.5,1.5,100\n",
+			wantedRequests: []string{
+				"/api/v2/networks/eth/pools/0xpool/ohlcv/hour?aggregate=4&currency=usd&limit=100"},
+		},
+		{name: "export without dataset", args: []string{"export", "prices"}, wantedCode: 1,
+			wantedStderr: "expected dataset: market-chart, ohlc, ohlcv, markets"},
+		{name: "export help", args: []string{"export", "ohlc", "-h"}, wantedStderr: "usage: cgcli export ohlc <id>"},
+		{name: "api error", args: []string{"ping"}, wantedCode: 1, wantedStderr: "cgcli: ",
+			wantedRequests: []string{"/api/v3/ping?"}},
+		{name: "missing argument", args: []string{"coin"}, wantedCode: 1, wantedStderr: "expected 1 arguments"},
+		{name: "unknown command", args: []string{"prices"}, wantedCode: 2, wantedStderr: "unknown command"},
+		{name: "help", args: []string{"help", "pool"}, wantedStderr: "usage: cgcli pool <network> <addresses>"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			var stdout, stderr bytes.Buffer
+			code := run(context.TODO(), tt.args, &stdout, &stderr, baseURL)
+			if code != tt.wantedCode {
+				t.Fatalf("incorrect exit code, wanted: %d, got: %d, stderr: %s", tt.wantedCode, code, stderr.String())
+			}
+			if stdout.String() != tt.wantedOutput {
+				t.Fatalf("incorrect output, wanted: %q, got: %q", tt.wantedOutput, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantedStderr) {
+				t.Fatalf("incorrect stderr, wanted: %q, got: %q", tt.wantedStderr, stderr.String())
+			}
+			if strings.Join(requests, " ") != strings.Join(tt.wantedRequests, " ") {
+				t.Fatalf("incorrect requests, wanted: %v, got: %v", tt.wantedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestClients_Config(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"api_key":"file-key","rate_limit":10,"output":"json"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CGCLI_CONFIG", path)
+	t.Setenv("COINGECKO_API_KEY", "env-key")
+	t.Setenv("COINGECKO_PRO", "true")
+
+	g := &globals{output: "csv"}
+	c := &clients{globals: g}
+	cfg, err := c.config()
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if cfg.APIKey != "env-key" || !cfg.Pro || cfg.Output != "csv" || cfg.RateLimit == nil || *cfg.RateLimit != 10 {
+		t.Fatalf("incorrect config, got: %+v", cfg)
+	}
+
+	t.Setenv("CGCLI_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err = (&clients{globals: g}).config(); err == nil {
+		t.Fatal("error should not be nil for a missing config file")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	wanted := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	for _, s := range []string{"1698796800", "2023-11-01T00:00:00Z", "2023-11-01"} {
+		if result, err := parseTime(s); err != nil || !result.Equal(wanted) {
+			t.Fatalf("incorrect time of %s: %v, error: %v", s, result, err)
+		}
+	}
+	if _, err := parseTime("01/11/2023"); err == nil {
+		t.Fatal("error should not be nil")
+	}
+}
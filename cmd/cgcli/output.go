@@ -0,0 +1,537 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+
+	// maxCellWidth is the maximum width of table cells, longer cells are truncated. CSV cells are never truncated.
+	maxCellWidth = 60
+)
+
+// write writes v to w in format. Table and CSV output only contain columns if it is not empty.
+func write(w io.Writer, format string, v any, columns []string) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case formatTable, formatCSV:
+		t, err := tabulate(v)
+		if err != nil {
+			return err
+		}
+		if len(columns) != 0 {
+			if t, err = t.project(columns); err != nil {
+				return err
+			}
+		}
+		if format == formatCSV {
+			return t.writeCSV(w)
+		}
+		return t.writeTable(w)
+	default:
+		return fmt.Errorf("unknown output format %q, valid values: table, json, csv", format)
+	}
+}
+
+// table is the tabular form of an API response.
+type table struct {
+	columns []string
+	rows    [][]string
+}
+
+func (t *table) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(t.columns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(t.rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func (t *table) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.columns, "\t")))
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = truncate(strings.Join(strings.Fields(cell), " "), maxCellWidth)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// project returns a table with columns only.
+func (t *table) project(columns []string) (*table, error) {
+	index := make(map[string]int, len(t.columns))
+	for i, column := range t.columns {
+		index[column] = i
+	}
+	result := &table{columns: columns, rows: make([][]string, len(t.rows))}
+	for _, column := range columns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("unknown column %q, available columns: %s", column, strings.Join(t.columns, ","))
+		}
+	}
+	for i, row := range t.rows {
+		result.rows[i] = make([]string, len(columns))
+		for j, column := range columns {
+			result.rows[i][j] = row[index[column]]
+		}
+	}
+	return result, nil
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// object is a decoded JSON object which keeps the order of its keys, so columns follow the order of struct fields.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *object) get(key string) (any, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+// decodeValue decodes the next JSON value of decoder into *object, []any, json.Number, string, bool or nil.
+func decodeValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		obj := &object{values: make(map[string]any)}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			k := key.(string)
+			if _, ok := obj.values[k]; !ok {
+				obj.keys = append(obj.keys, k)
+			}
+			obj.values[k] = value
+		}
+		_, err = decoder.Token()
+		return obj, err
+	case json.Delim('['):
+		items := []any{}
+		for decoder.More() {
+			item, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err = decoder.Token()
+		return items, err
+	default:
+		return token, nil
+	}
+}
+
+// tabulate converts v into a table through its JSON form:
+//   - arrays of objects have one row per object, nested objects are flattened into dotted columns;
+//   - arrays of numeric arrays are candles or chart points with a time column;
+//   - objects of chart series are merged by time;
+//   - objects of objects, e.g. SimplePrice, have one row per key in the id column;
+//   - other objects have one row per flattened key.
+//
+// Wrappers with a single field, JSON:API data and the attributes of GeckoTerminal resources are unwrapped.
+func tabulate(v any) (*table, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := unwrap(value).(type) {
+	case []any:
+		return tabulateArray(value), nil
+	case *object:
+		if isChart(value) {
+			return tabulateChart(value), nil
+		}
+		if isKeyedObjects(value) {
+			var rows []*object
+			for _, key := range value.keys {
+				row := &object{keys: []string{"id"}, values: map[string]any{"id": key}}
+				flatten(row, "", resource(value.values[key].(*object)))
+				rows = append(rows, row)
+			}
+			return tabulateObjects(rows), nil
+		}
+		flat := &object{values: make(map[string]any)}
+		flatten(flat, "", resource(value))
+		t := &table{columns: []string{"key", "value"}}
+		for _, key := range flat.keys {
+			t.rows = append(t.rows, []string{key, cell(flat.values[key])})
+		}
+		return t, nil
+	default:
+		return &table{columns: []string{"value"}, rows: [][]string{{cell(value)}}}, nil
+	}
+}
+
+// unwrap returns the payload of wrapper objects.
+func unwrap(value any) any {
+	for {
+		obj, ok := value.(*object)
+		if !ok {
+			return value
+		}
+		if data, ok := obj.get("data"); ok && onlyKeys(obj, "data", "included", "meta", "links") {
+			value = data
+			continue
+		}
+		if len(obj.keys) == 1 {
+			inner := obj.values[obj.keys[0]]
+			// a single object of scalars is a row, e.g. SimplePrice of one coin.
+			if innerObj, ok := inner.(*object); ok && !isScalars(innerObj) {
+				value = inner
+				continue
+			}
+			if _, ok := inner.([]any); ok {
+				value = inner
+				continue
+			}
+		}
+		// e.g. CoinTickersResponse, the name of the coin and its tickers.
+		if list, ok := soleObjectList(obj); ok {
+			value = list
+			continue
+		}
+		return value
+	}
+}
+
+func onlyKeys(obj *object, keys ...string) bool {
+	for _, key := range obj.keys {
+		found := false
+		for _, k := range keys {
+			found = found || k == key
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// soleObjectList returns the only field of obj which is a non-empty array of objects, if the other fields are
+// scalars.
+func soleObjectList(obj *object) ([]any, bool) {
+	var list []any
+	for _, key := range obj.keys {
+		switch value := obj.values[key].(type) {
+		case *object:
+			return nil, false
+		case []any:
+			if list != nil || len(value) == 0 {
+				return nil, false
+			}
+			if _, ok := value[0].(*object); !ok {
+				return nil, false
+			}
+			list = value
+		}
+	}
+	return list, list != nil
+}
+
+// resource returns the attributes of a GeckoTerminal resource with its id, or obj itself.
+func resource(obj *object) *object {
+	attributes, ok := obj.get("attributes")
+	if _, isResource := obj.get("type"); !ok || !isResource {
+		return obj
+	}
+	attrs, ok := attributes.(*object)
+	if !ok {
+		return obj
+	}
+	result := &object{values: make(map[string]any)}
+	if id, ok := obj.get("id"); ok {
+		result.keys = append(result.keys, "id")
+		result.values["id"] = id
+	}
+	for _, key := range attrs.keys {
+		if _, ok := result.values[key]; !ok {
+			result.keys = append(result.keys, key)
+		}
+		result.values[key] = attrs.values[key]
+	}
+	return result
+}
+
+// flatten adds the fields of obj to flat, nested objects are added with dotted keys.
+func flatten(flat *object, prefix string, obj *object) {
+	for _, key := range obj.keys {
+		name := prefix + key
+		if nested, ok := obj.values[key].(*object); ok && len(nested.keys) != 0 {
+			flatten(flat, name+".", nested)
+			continue
+		}
+		if _, ok := flat.values[name]; !ok {
+			flat.keys = append(flat.keys, name)
+		}
+		flat.values[name] = obj.values[key]
+	}
+}
+
+func isScalars(obj *object) bool {
+	for _, key := range obj.keys {
+		switch obj.values[key].(type) {
+		case *object, []any:
+			return false
+		}
+	}
+	return true
+}
+
+func isKeyedObjects(obj *object) bool {
+	for _, key := range obj.keys {
+		if _, ok := obj.values[key].(*object); !ok {
+			return false
+		}
+	}
+	return len(obj.keys) != 0
+}
+
+// isChart reports whether all fields of obj are series of [time, value] points.
+func isChart(obj *object) bool {
+	for _, key := range obj.keys {
+		series, ok := obj.values[key].([]any)
+		if !ok {
+			return false
+		}
+		for _, item := range series {
+			if point, ok := item.([]any); !ok || len(point) != 2 {
+				return false
+			}
+		}
+	}
+	return len(obj.keys) != 0
+}
+
+func tabulateChart(obj *object) *table {
+	t := &table{columns: append([]string{"time"}, obj.keys...)}
+	rows := make(map[string][]string)
+	var times []string
+	for i, key := range obj.keys {
+		for _, item := range obj.values[key].([]any) {
+			point := item.([]any)
+			ts := cell(point[0])
+			row, ok := rows[ts]
+			if !ok {
+				row = make([]string, len(t.columns))
+				row[0] = formatTime(point[0])
+				rows[ts] = row
+				times = append(times, ts)
+			}
+			row[i+1] = cell(point[1])
+		}
+	}
+	sort.SliceStable(times, func(i, j int) bool { return numberLess(times[i], times[j]) })
+	for _, ts := range times {
+		t.rows = append(t.rows, rows[ts])
+	}
+	return t
+}
+
+// candleColumns names the elements of numeric arrays by their length.
+var candleColumns = map[int][]string{
+	2: {"time", "value"},
+	5: {"time", "open", "high", "low", "close"},
+	6: {"time", "open", "high", "low", "close", "volume"},
+}
+
+func tabulateArray(items []any) *table {
+	if len(items) == 0 {
+		return &table{}
+	}
+	switch first := items[0].(type) {
+	case *object:
+		rows := make([]*object, 0, len(items))
+		for _, item := range items {
+			obj, ok := item.(*object)
+			if !ok {
+				return tabulateScalars(items)
+			}
+			row := &object{values: make(map[string]any)}
+			flatten(row, "", resource(obj))
+			rows = append(rows, row)
+		}
+		return tabulateObjects(rows)
+	case []any:
+		columns, ok := candleColumns[len(first)]
+		if !ok {
+			return tabulateScalars(items)
+		}
+		t := &table{columns: columns}
+		for _, item := range items {
+			values, ok := item.([]any)
+			if !ok || len(values) != len(columns) {
+				return tabulateScalars(items)
+			}
+			row := []string{formatTime(values[0])}
+			for _, value := range values[1:] {
+				row = append(row, cell(value))
+			}
+			t.rows = append(t.rows, row)
+		}
+		return t
+	default:
+		return tabulateScalars(items)
+	}
+}
+
+func tabulateScalars(items []any) *table {
+	t := &table{columns: []string{"value"}}
+	for _, item := range items {
+		t.rows = append(t.rows, []string{cell(item)})
+	}
+	return t
+}
+
+// tabulateObjects has one column per key of any row, in order of first appearance.
+func tabulateObjects(rows []*object) *table {
+	t := &table{}
+	index := make(map[string]int)
+	for _, row := range rows {
+		for _, key := range row.keys {
+			if _, ok := index[key]; !ok {
+				index[key] = len(t.columns)
+				t.columns = append(t.columns, key)
+			}
+		}
+	}
+	for _, row := range rows {
+		cells := make([]string, len(t.columns))
+		for _, key := range row.keys {
+			cells[index[key]] = cell(row.values[key])
+		}
+		t.rows = append(t.rows, cells)
+	}
+	return t
+}
+
+// cell formats a decoded JSON value, arrays of scalars are joined by commas and other arrays are kept as JSON.
+func cell(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return fmt.Sprint(value)
+	case []any:
+		parts := make([]string, 0, len(value))
+		for _, item := range value {
+			switch item.(type) {
+			case *object, []any:
+				return compact(value)
+			}
+			parts = append(parts, cell(item))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return compact(value)
+	}
+}
+
+// compact encodes a decoded JSON value back into JSON.
+func compact(value any) string {
+	var buf bytes.Buffer
+	writeJSON(&buf, value)
+	return buf.String()
+}
+
+func writeJSON(buf *bytes.Buffer, value any) {
+	switch value := value.(type) {
+	case *object:
+		buf.WriteByte('{')
+		for i, key := range value.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			data, _ := json.Marshal(key)
+			buf.Write(data)
+			buf.WriteByte(':')
+			writeJSON(buf, value.values[key])
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSON(buf, item)
+		}
+		buf.WriteByte(']')
+	default:
+		data, _ := json.Marshal(value)
+		buf.Write(data)
+	}
+}
+
+// formatTime formats a unix timestamp in seconds or milliseconds as RFC 3339 time, other values are kept.
+func formatTime(value any) string {
+	number, ok := value.(json.Number)
+	if !ok {
+		return cell(value)
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return number.String()
+	}
+	if f > 1e11 {
+		return time.UnixMilli(int64(f)).UTC().Format(time.RFC3339)
+	}
+	return time.Unix(int64(f), 0).UTC().Format(time.RFC3339)
+}
+
+func numberLess(a, b string) bool {
+	x, errX := json.Number(a).Float64()
+	y, errY := json.Number(b).Float64()
+	if errX != nil || errY != nil {
+		return a < b
+	}
+	return x < y
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+func TestTabulate(t *testing.T) {
+	cases := []struct {
+		name          string
+		value         any
+		wantedColumns []string
+		wantedRows    [][]string
+	}{
+		{
+			name:          "keyed objects",
+			value:         map[string]map[string]float64{"bitcoin": {"usd": 42000}},
+			wantedColumns: []string{"id", "usd"},
+			wantedRows:    [][]string{{"bitcoin", "42000"}},
+		},
+		{
+			name: "chart",
+			value: coingecko.CoinMarketChartDataResponse{
+				Prices:       []coingecko.ChartItem{{1698796800000, 42000}},
+				MarketCaps:   []coingecko.ChartItem{{1698796800000, 8e11}},
+				TotalVolumes: []coingecko.ChartItem{{1698796800000, 1e10}},
+			},
+			wantedColumns: []string{"time", "prices", "market_caps", "total_volumes"},
+			wantedRows:    [][]string{{"2023-11-01T00:00:00Z", "42000", "800000000000", "10000000000"}},
+		},
+		{
+			name:          "candles",
+			value:         [][5]float64{{1698796800000, 1, 2, 0.5, 1.5}},
+			wantedColumns: []string{"time", "open", "high", "low", "close"},
+			wantedRows:    [][]string{{"2023-11-01T00:00:00Z", "1", "2", "0.5", "1.5"}},
+		},
+		{
+			name: "json api resources",
+			value: map[string]any{"data": []any{
+				map[string]any{"id": "eth_0x1", "type": "pool", "attributes": map[string]any{"name": "WETH / USDC"}},
+			}},
+			wantedColumns: []string{"id", "name"},
+			wantedRows:    [][]string{{"eth_0x1", "WETH / USDC"}},
+		},
+		{
+			name: "wrapped list",
+			value: coingecko.CoinTickersResponse{Name: "Bitcoin", Tickers: []coingecko.TickersItem{
+				{Base: "BTC", Target: "USDT"}}},
+			wantedColumns: nil,
+			wantedRows:    [][]string{{"BTC", "USDT"}},
+		},
+		{
+			name:          "object",
+			value:         map[string]any{"A": 1, "B": map[string]any{"C": []string{"x", "y"}}},
+			wantedColumns: []string{"key", "value"},
+			wantedRows:    [][]string{{"A", "1"}, {"B.C", "x,y"}},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tabulate(tt.value)
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if tt.wantedColumns == nil {
+				// only check the wanted columns of wide tables.
+				if result, err = result.project([]string{"base", "target"}); err != nil {
+					t.Fatalf("error should be nil, got: %v", err)
+				}
+			} else if !reflect.DeepEqual(result.columns, tt.wantedColumns) {
+				t.Fatalf("incorrect columns, wanted: %v, got: %v", tt.wantedColumns, result.columns)
+			}
+			if !reflect.DeepEqual(result.rows, tt.wantedRows) {
+				t.Fatalf("incorrect rows, wanted: %v, got: %v", tt.wantedRows, result.rows)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	value := []map[string]any{{"id": "bitcoin", "name": "Bitcoin, the first"}}
+	cases := []struct {
+		format      string
+		columns     []string
+		wanted      string
+		wantedIsErr bool
+	}{
+		{format: formatTable, wanted: "ID       NAME\nbitcoin  Bitcoin, the first\n"},
+		{format: formatCSV, columns: []string{"name"}, wanted: "name\n\"Bitcoin, the first\"\n"},
+		{format: formatJSON, wanted: "[\n  {\n    \"id\": \"bitcoin\",\n    \"name\": \"Bitcoin, the first\"\n  }\n]\n"},
+		{format: formatCSV, columns: []string{"symbol"}, wantedIsErr: true},
+		{format: "xml", wantedIsErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := write(&buf, tt.format, value, tt.columns)
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if buf.String() != tt.wanted {
+				t.Fatalf("incorrect output, wanted: %q, got: %q", tt.wanted, buf.String())
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if s := truncate(strings.Repeat("é", 10), 5); s != "éééé…" {
+		t.Fatalf("incorrect result, got: %s", s)
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultInterval = time.Minute
+
+// config is the list of coins, pools and tokens to export, read from a YAML file:
+//
+//	interval: 1m
+//	vs_currencies: [usd, eur]
+//	global: true
+//	coins:
+//	  - bitcoin
+//	  - ethereum
+//	pools:
+//	  - network: eth
+//	    address: 0x60594a405d53811d3bc4766596efd80fd545a270
+//	tokens:
+//	  - network: eth
+//	    address: 0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2
+//
+// Only this subset of YAML is supported: top-level keys with scalars, lists of scalars (block or [a, b] style) or
+// lists of mappings of scalars, and # comments.
+type config struct {
+	// Interval is how often the metrics are refreshed, 1m by default.
+	Interval time.Duration
+	// VsCurrencies are the lowercase target currencies of coin prices and global data, usd by default.
+	VsCurrencies []string
+	// Global tells whether to export global market data, true by default.
+	Global bool
+	Coins  []string
+	Pools  []asset
+	Tokens []asset
+}
+
+// asset is a pool or token of GeckoTerminal.
+type asset struct {
+	Network string
+	Address string
+}
+
+// loadConfig reads the config file at path.
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// parseConfig parses a YAML config.
+func parseConfig(data []byte) (*config, error) {
+	values, err := parseYAML(string(data))
+	if err != nil {
+		return nil, err
+	}
+	cfg := &config{Interval: defaultInterval, VsCurrencies: []string{"usd"}, Global: true}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		v := values[key]
+		switch key {
+		case "interval":
+			if cfg.Interval, err = time.ParseDuration(v.scalar); err != nil || cfg.Interval <= 0 {
+				return nil, fmt.Errorf("line %d: invalid interval %q", v.line, v.scalar)
+			}
+		case "global":
+			if cfg.Global, err = strconv.ParseBool(v.scalar); err != nil {
+				return nil, fmt.Errorf("line %d: invalid global %q", v.line, v.scalar)
+			}
+		case "vs_currencies":
+			if cfg.VsCurrencies, err = v.strings(key); err != nil {
+				return nil, err
+			}
+			// the API returns lowercase currency keys.
+			for i, vs := range cfg.VsCurrencies {
+				cfg.VsCurrencies[i] = strings.ToLower(vs)
+			}
+		case "coins":
+			if cfg.Coins, err = v.strings(key); err != nil {
+				return nil, err
+			}
+		case "pools":
+			if cfg.Pools, err = v.assets(key); err != nil {
+				return nil, err
+			}
+		case "tokens":
+			if cfg.Tokens, err = v.assets(key); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %s", v.line, key)
+		}
+	}
+	if len(cfg.VsCurrencies) == 0 {
+		return nil, fmt.Errorf("vs_currencies should not be empty")
+	}
+	if len(cfg.Coins) == 0 && len(cfg.Pools) == 0 && len(cfg.Tokens) == 0 && !cfg.Global {
+		return nil, fmt.Errorf("nothing to export, add coins, pools, tokens or global")
+	}
+	return cfg, nil
+}
+
+// yamlValue is a value of the supported YAML subset: a scalar, a list of scalars or a list of mappings.
+type yamlValue struct {
+	line   int
+	scalar string
+	list   []string
+	maps   []yamlMap
+}
+
+// yamlMap is a mapping item of a list.
+type yamlMap struct {
+	line   int
+	values map[string]string
+}
+
+// strings returns v as a list of strings.
+func (v *yamlValue) strings(key string) ([]string, error) {
+	if len(v.maps) > 0 || v.scalar != "" {
+		return nil, fmt.Errorf("line %d: %s should be a list", v.line, key)
+	}
+	return v.list, nil
+}
+
+// assets returns v as a list of assets.
+func (v *yamlValue) assets(key string) ([]asset, error) {
+	if len(v.list) > 0 || v.scalar != "" {
+		return nil, fmt.Errorf("line %d: %s should be a list of network and address", v.line, key)
+	}
+	assets := make([]asset, 0, len(v.maps))
+	for _, m := range v.maps {
+		var a asset
+		for k, value := range m.values {
+			switch k {
+			case "network":
+				a.Network = value
+			case "address":
+				a.Address = value
+			default:
+				return nil, fmt.Errorf("line %d: unknown key %s of %s", m.line, k, key)
+			}
+		}
+		if a.Network == "" || a.Address == "" {
+			return nil, fmt.Errorf("line %d: network and address of %s should not be empty", m.line, key)
+		}
+		assets = append(assets, a)
+	}
+	return assets, nil
+}
+
+// parseYAML parses the top-level keys of the supported YAML subset.
+func parseYAML(data string) (map[string]*yamlValue, error) {
+	values := make(map[string]*yamlValue)
+	var (
+		block      *yamlValue // the value of a key without inline value
+		item       *yamlMap   // the mapping item being parsed
+		itemIndent int
+	)
+	for i, raw := range strings.Split(data, "\n") {
+		n := i + 1
+		line := strings.TrimRight(stripComment(raw), " \t\r")
+		text := strings.TrimLeft(line, " ")
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed in indentation", n)
+		}
+		indent := len(line) - len(text)
+		isItem := text == "-" || strings.HasPrefix(text, "- ")
+
+		switch {
+		case indent == 0 && !isItem:
+			key, value, ok := splitKeyValue(text)
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key: value", n)
+			}
+			if _, ok = values[key]; ok {
+				return nil, fmt.Errorf("line %d: duplicate key %s", n, key)
+			}
+			v := &yamlValue{line: n}
+			values[key] = v
+			block, item = nil, nil
+			switch {
+			case value == "":
+				block = v
+			case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+				v.list = splitFlowList(value[1 : len(value)-1])
+			default:
+				v.scalar = unquote(value)
+			}
+		case block == nil:
+			return nil, fmt.Errorf("line %d: unexpected indentation", n)
+		case isItem:
+			rest := strings.TrimSpace(strings.TrimPrefix(text, "-"))
+			key, value, ok := splitKeyValue(rest)
+			if ok && !isQuoted(rest) {
+				if len(block.list) > 0 {
+					return nil, fmt.Errorf("line %d: mixed list of scalars and mappings", n)
+				}
+				block.maps = append(block.maps, yamlMap{line: n, values: map[string]string{key: unquote(value)}})
+				item, itemIndent = &block.maps[len(block.maps)-1], indent+len(text)-len(rest)
+				continue
+			}
+			if len(block.maps) > 0 {
+				return nil, fmt.Errorf("line %d: mixed list of scalars and mappings", n)
+			}
+			block.list = append(block.list, unquote(rest))
+			item = nil
+		default:
+			key, value, ok := splitKeyValue(text)
+			if item == nil || indent != itemIndent || !ok {
+				return nil, fmt.Errorf("line %d: unexpected indentation", n)
+			}
+			if _, ok = item.values[key]; ok {
+				return nil, fmt.Errorf("line %d: duplicate key %s", n, key)
+			}
+			item.values[key] = unquote(value)
+		}
+	}
+	return values, nil
+}
+
+// stripComment removes the # comment of line, # inside quotes or words is kept.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitKeyValue splits "key: value" or "key:", the key must not contain spaces or quotes.
+func splitKeyValue(text string) (string, string, bool) {
+	i := strings.Index(text, ":")
+	if i <= 0 || (i+1 < len(text) && text[i+1] != ' ') {
+		return "", "", false
+	}
+	key := text[:i]
+	if strings.ContainsAny(key, " \"'") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(text[i+1:]), true
+}
+
+func splitFlowList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, unquote(item))
+		}
+	}
+	return items
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'')
+}
+
+// unquote removes the quotes of a double or single quoted scalar.
+func unquote(s string) string {
+	if !isQuoted(s) {
+		return s
+	}
+	if s[0] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		return unquoted
+	}
+	return s[1 : len(s)-1]
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	data := `# exported assets
+interval: 30s
+vs_currencies: [USD, "eur"]
+coins:
+  - bitcoin
+  - 'ethereum' # the second coin
+pools:
+- network: eth
+  address: "0x60594a405d53811d3bc4766596efd80fd545a270"
+tokens:
+  - network: eth
+    address: 0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2
+  - address: 0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48
+    network: eth
+`
+	cfg, err := parseConfig([]byte(data))
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	wanted := &config{
+		Interval:     30 * time.Second,
+		VsCurrencies: []string{"usd", "eur"},
+		Global:       true,
+		Coins:        []string{"bitcoin", "ethereum"},
+		Pools:        []asset{{Network: "eth", Address: "0x60594a405d53811d3bc4766596efd80fd545a270"}},
+		Tokens: []asset{{Network: "eth", Address: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"},
+			{Network: "eth", Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"}},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Fatalf("incorrect config, wanted: %+v, got: %+v", wanted, cfg)
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	cases := []struct {
+		name        string
+		data        string
+		wantedError string
+	}{
+		{name: "unknown key", data: "coin: bitcoin", wantedError: "line 1: unknown key coin"},
+		{name: "duplicate key", data: "coins: [a]\ncoins: [b]", wantedError: "line 2: duplicate key coins"},
+		{name: "invalid interval", data: "interval: often", wantedError: "invalid interval"},
+		{name: "scalar list", data: "coins: bitcoin", wantedError: "coins should be a list"},
+		{name: "missing address", data: "pools:\n  - network: eth", wantedError: "network and address of pools"},
+		{name: "mixed list", data: "pools:\n  - eth\n  - network: eth", wantedError: "line 3: mixed list"},
+		{name: "indentation", data: "pools:\n  - network: eth\n      address: 0x1", wantedError: "line 3: unexpected"},
+		{name: "tab", data: "coins:\n\t- bitcoin", wantedError: "tabs are not allowed"},
+		{name: "empty", data: "global: false", wantedError: "nothing to export"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfig([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantedError) {
+				t.Fatalf("incorrect error, wanted: %s, got: %v", tt.wantedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+	"github.com/bufdata/coingecko-api/metrics"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	// coinsPerRequest is the number of coins of each SimplePrice request.
+	coinsPerRequest = 100
+	// addressesPerRequest is the maximum number of addresses of GeckoTerminal multi pools and tokens APIs.
+	addressesPerRequest = 30
+)
+
+// Sources of the collected data, used as the source label of the exporter metrics.
+const (
+	sourcePrices = "prices"
+	sourceGlobal = "global"
+	sourcePools  = "pools"
+	sourceTokens = "tokens"
+)
+
+// exporter refreshes the gauges of the configured coins, pools and tokens.
+type exporter struct {
+	cg  *coingecko.Client
+	gt  *geckoterminal.Client
+	cfg *config
+	now func() time.Time
+
+	price, marketCap, volume, priceChange                  *metrics.GaugeVec
+	globalMarketCap, globalVolume, globalDominance         *metrics.GaugeVec
+	globalMarketCapChange, globalActive                    *metrics.GaugeVec
+	poolReserve, poolVolume, poolBasePrice, poolQuotePrice *metrics.GaugeVec
+	tokenPrice, tokenReserve, tokenVolume                  *metrics.GaugeVec
+	lastSuccess                                            *metrics.GaugeVec
+	errors                                                 *metrics.CounterVec
+}
+
+// newExporter registers the gauges of cfg in r.
+func newExporter(r *metrics.Registry, cg *coingecko.Client, gt *geckoterminal.Client, cfg *config) *exporter {
+	return &exporter{
+		cg:  cg,
+		gt:  gt,
+		cfg: cfg,
+		now: time.Now,
+
+		price:     r.NewGaugeVec("coingecko_price", "Price of a coin.", "coin", "vs_currency"),
+		marketCap: r.NewGaugeVec("coingecko_market_cap", "Market cap of a coin.", "coin", "vs_currency"),
+		volume:    r.NewGaugeVec("coingecko_volume_24h", "24h trading volume of a coin.", "coin", "vs_currency"),
+		priceChange: r.NewGaugeVec("coingecko_price_change_24h_percent", "24h price change of a coin in percent.",
+			"coin", "vs_currency"),
+
+		globalMarketCap: r.NewGaugeVec("coingecko_global_market_cap", "Total market cap of all coins.",
+			"vs_currency"),
+		globalVolume: r.NewGaugeVec("coingecko_global_volume_24h", "Total 24h trading volume of all coins.",
+			"vs_currency"),
+		globalDominance: r.NewGaugeVec("coingecko_global_market_cap_percentage",
+			"Share of the total market cap of the largest coins in percent.", "coin"),
+		globalMarketCapChange: r.NewGaugeVec("coingecko_global_market_cap_change_24h_percent",
+			"24h change of the total market cap in USD in percent."),
+		globalActive: r.NewGaugeVec("coingecko_global_active_cryptocurrencies",
+			"Number of active cryptocurrencies."),
+
+		poolReserve: r.NewGaugeVec("geckoterminal_pool_reserve_usd", "Reserve of a pool in USD.",
+			"network", "pool"),
+		poolVolume: r.NewGaugeVec("geckoterminal_pool_volume_24h_usd", "24h trading volume of a pool in USD.",
+			"network", "pool"),
+		poolBasePrice: r.NewGaugeVec("geckoterminal_pool_base_token_price_usd",
+			"Price of the base token of a pool in USD.", "network", "pool"),
+		poolQuotePrice: r.NewGaugeVec("geckoterminal_pool_quote_token_price_usd",
+			"Price of the quote token of a pool in USD.", "network", "pool"),
+
+		tokenPrice: r.NewGaugeVec("geckoterminal_token_price_usd", "Price of a token in USD.", "network", "token"),
+		tokenReserve: r.NewGaugeVec("geckoterminal_token_total_reserve_usd",
+			"Total reserve of a token in all pools in USD.", "network", "token"),
+		tokenVolume: r.NewGaugeVec("geckoterminal_token_volume_24h_usd", "24h trading volume of a token in USD.",
+			"network", "token"),
+
+		lastSuccess: r.NewGaugeVec("cgexporter_last_success_timestamp_seconds",
+			"Unix time of the last successful refresh of a source.", "source"),
+		errors: r.NewCounterVec("cgexporter_errors_total", "Number of failed refreshes of a source.", "source"),
+	}
+}
+
+// run refreshes the gauges now and then every cfg.Interval until ctx is done.
+func (e *exporter) run(ctx context.Context) error {
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := e.collect(ctx); err != nil {
+			slog.Error("failed to collect metrics", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect refreshes all gauges. A failed source keeps its previous values and does not stop the others.
+func (e *exporter) collect(ctx context.Context) error {
+	var errs []error
+	sources := []struct {
+		name    string
+		enabled bool
+		collect func(context.Context) error
+	}{
+		{name: sourcePrices, enabled: len(e.cfg.Coins) > 0, collect: e.collectPrices},
+		{name: sourceGlobal, enabled: e.cfg.Global, collect: e.collectGlobal},
+		{name: sourcePools, enabled: len(e.cfg.Pools) > 0, collect: e.collectPools},
+		{name: sourceTokens, enabled: len(e.cfg.Tokens) > 0, collect: e.collectTokens},
+	}
+	for _, source := range sources {
+		if !source.enabled {
+			continue
+		}
+		if err := source.collect(ctx); err != nil {
+			e.errors.Inc(source.name)
+			errs = append(errs, fmt.Errorf("%s: %w", source.name, err))
+			continue
+		}
+		e.lastSuccess.Set(float64(e.now().Unix()), source.name)
+	}
+	return errors.Join(errs...)
+}
+
+func (e *exporter) collectPrices(ctx context.Context) error {
+	for start := 0; start < len(e.cfg.Coins); start += coinsPerRequest {
+		ids := e.cfg.Coins[start:min(start+coinsPerRequest, len(e.cfg.Coins))]
+		data, err := e.cg.SimplePrice(ctx, ids, e.cfg.VsCurrencies, "true", "true", "true", "", "")
+		if err != nil {
+			return err
+		}
+		for id, values := range *data {
+			for _, vs := range e.cfg.VsCurrencies {
+				setIfPresent(e.price, values, vs, id, vs)
+				setIfPresent(e.marketCap, values, vs+"_market_cap", id, vs)
+				setIfPresent(e.volume, values, vs+"_24h_vol", id, vs)
+				setIfPresent(e.priceChange, values, vs+"_24h_change", id, vs)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) collectGlobal(ctx context.Context) error {
+	data, err := e.cg.GetGlobalCryptocurrencyData(ctx)
+	if err != nil {
+		return err
+	}
+	for _, vs := range e.cfg.VsCurrencies {
+		setIfPresent(e.globalMarketCap, data.Data.TotalMarketCap, vs, vs)
+		setIfPresent(e.globalVolume, data.Data.TotalVolume, vs, vs)
+	}
+	for coin, percentage := range data.Data.MarketCapPercentage {
+		e.globalDominance.Set(percentage, coin)
+	}
+	e.globalMarketCapChange.Set(data.Data.MarketCapChangePercentage24hUSD)
+	e.globalActive.Set(float64(data.Data.ActiveCryptoCurrencies))
+	return nil
+}
+
+func (e *exporter) collectPools(ctx context.Context) error {
+	return forEachBatch(e.cfg.Pools, func(network string, addresses []string) error {
+		data, err := e.gt.GetMultiPools(ctx, network, nil, addresses)
+		if err != nil {
+			return err
+		}
+		for _, pool := range data.Data {
+			attrs := &pool.Attributes
+			address := util.NormalizeAddress(attrs.Address)
+			setDecimal(e.poolReserve, attrs.ReserveInUSDDecimal, network, address)
+			setDecimal(e.poolVolume, func() (*geckoterminal.Decimal, error) { return attrs.VolumeUSDDecimal("h24") },
+				network, address)
+			setDecimal(e.poolBasePrice, attrs.BaseTokenPriceUSDDecimal, network, address)
+			setDecimal(e.poolQuotePrice, attrs.QuoteTokenPriceUSDDecimal, network, address)
+		}
+		return nil
+	})
+}
+
+func (e *exporter) collectTokens(ctx context.Context) error {
+	return forEachBatch(e.cfg.Tokens, func(network string, addresses []string) error {
+		data, err := e.gt.GetMultiTokensOnOneNetwork(ctx, network, addresses, nil)
+		if err != nil {
+			return err
+		}
+		for _, token := range data.Data {
+			attrs := &token.Attributes
+			address := util.NormalizeAddress(attrs.Address)
+			setDecimal(e.tokenPrice, attrs.PriceUSDDecimal, network, address)
+			setDecimal(e.tokenReserve, attrs.TotalReserveInUSDDecimal, network, address)
+			setDecimal(e.tokenVolume, func() (*geckoterminal.Decimal, error) { return attrs.VolumeUSDDecimal("h24") },
+				network, address)
+		}
+		return nil
+	})
+}
+
+// forEachBatch calls fn with the addresses of assets grouped by network, in batches of addressesPerRequest. A failed
+// batch does not stop the others, the errors of all batches are joined.
+func forEachBatch(assets []asset, fn func(network string, addresses []string) error) error {
+	var networks []string
+	addresses := make(map[string][]string)
+	for _, a := range assets {
+		if _, ok := addresses[a.Network]; !ok {
+			networks = append(networks, a.Network)
+		}
+		addresses[a.Network] = append(addresses[a.Network], a.Address)
+	}
+	var errs []error
+	for _, network := range networks {
+		list := addresses[network]
+		for start := 0; start < len(list); start += addressesPerRequest {
+			if err := fn(network, list[start:min(start+addressesPerRequest, len(list))]); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", network, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setIfPresent sets the gauge of labelValues to values[key] if it exists.
+func setIfPresent(g *metrics.GaugeVec, values map[string]float64, key string, labelValues ...string) {
+	if value, ok := values[key]; ok {
+		g.Set(value, labelValues...)
+	}
+}
+
+// setDecimal sets the gauge of labelValues to the value of get, absent or invalid values are skipped.
+func setDecimal(g *metrics.GaugeVec, get func() (*geckoterminal.Decimal, error), labelValues ...string) {
+	d, err := get()
+	if err != nil {
+		slog.Error("failed to parse decimal", "labels", labelValues, "error", err)
+		return
+	}
+	if d != nil {
+		g.Set(d.Float64(), labelValues...)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/metrics"
+)
+
+func TestExporter_Collect(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/simple/price"):
+			_, _ = w.Write([]byte(`{"bitcoin":{"usd":42000,"usd_market_cap":8.2e11,"usd_24h_vol":1.5e10,
+"usd_24h_change":-1.25}}`))
+		case strings.HasSuffix(r.URL.Path, "/global"):
+			_, _ = w.Write([]byte(`{"data":{"active_cryptocurrencies":10000,"total_market_cap":{"usd":1.6e12,"eur":1.5e12},
+"total_volume":{"usd":5e10},"market_cap_percentage":{"btc":51.5},"market_cap_change_percentage_24h_usd":0.5}}`))
+		case strings.Contains(r.URL.Path, "/solana/pools/multi/"):
+			_, _ = w.Write([]byte(`{"data":[{"id":"solana_Czfq","type":"pool","attributes":{"address":"Czfq",
+"reserve_in_usd":"5"}}]}`))
+		case strings.Contains(r.URL.Path, "/pools/multi/"):
+			_, _ = w.Write([]byte(`{"data":[{"id":"eth_0x1","type":"pool","attributes":{"address":"0xAB",
+"reserve_in_usd":"1000000.5","base_token_price_usd":"2200","quote_token_price_usd":"1","volume_usd":{"h24":"250000"}}}]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	opts := &options{coinGeckoURL: server.URL + "/api/v3", geckoTerminalURL: server.URL + "/api/v2"}
+	r := metrics.NewRegistry()
+	cg, gt := newClients(opts, metrics.NewClientMetrics(r))
+	cfg := &config{
+		Interval:     time.Minute,
+		VsCurrencies: []string{"usd"},
+		Global:       true,
+		Coins:        []string{"bitcoin"},
+		Pools:        []asset{{Network: "eth", Address: "0xab"}, {Network: "solana", Address: "Czfq"}},
+		Tokens:       []asset{{Network: "eth", Address: "0xcd"}},
+	}
+	e := newExporter(r, cg, gt, cfg)
+	e.now = func() time.Time { return time.Unix(1698796800, 0) }
+
+	err := e.collect(context.TODO())
+	if err == nil || !strings.Contains(err.Error(), "tokens: ") {
+		t.Fatalf("error of tokens should not be nil, got: %v", err)
+	}
+	var buf bytes.Buffer
+	if err = r.Write(&buf); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	for _, wanted := range []string{
+		`coingecko_price{coin="bitcoin",vs_currency="usd"} 42000`,
+		`coingecko_market_cap{coin="bitcoin",vs_currency="usd"} 8.2e+11`,
+		`coingecko_volume_24h{coin="bitcoin",vs_currency="usd"} 1.5e+10`,
+		`coingecko_price_change_24h_percent{coin="bitcoin",vs_currency="usd"} -1.25`,
+		`coingecko_global_market_cap{vs_currency="usd"} 1.6e+12`,
+		`coingecko_global_market_cap_percentage{coin="btc"} 51.5`,
+		`coingecko_global_market_cap_change_24h_percent 0.5`,
+		`geckoterminal_pool_reserve_usd{network="eth",pool="0xab"} 1.0000005e+06`,
+		`geckoterminal_pool_volume_24h_usd{network="eth",pool="0xab"} 250000`,
+		`geckoterminal_pool_reserve_usd{network="solana",pool="Czfq"} 5`,
+		`geckoterminal_pool_base_token_price_usd{network="eth",pool="0xab"} 2200`,
+		`cgexporter_last_success_timestamp_seconds{source="pools"} 1.6987968e+09`,
+		`cgexporter_errors_total{source="tokens"} 1`,
+		`coingecko_client_requests_total{api="geckoterminal",route="/networks/{}/tokens/multi/{}",code="500"} 1`,
+	} {
+		if !strings.Contains(buf.String(), wanted) {
+			t.Fatalf("output should contain %s, got:\n%s", wanted, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), `vs_currency="eur"`) {
+		t.Fatal("only the configured currencies should be exported")
+	}
+}
+
+func TestForEachBatch(t *testing.T) {
+	var assets []asset
+	for i := 0; i < addressesPerRequest+1; i++ {
+		assets = append(assets, asset{Network: "eth", Address: "0x1"})
+	}
+	assets = append(assets, asset{Network: "bsc", Address: "0x2"})
+	var batches []string
+	err := forEachBatch(assets, func(network string, addresses []string) error {
+		batches = append(batches, network+":"+strings.Repeat("x", len(addresses)))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	wanted := []string{"eth:" + strings.Repeat("x", addressesPerRequest), "eth:x", "bsc:x"}
+	if strings.Join(batches, " ") != strings.Join(wanted, " ") {
+		t.Fatalf("incorrect batches, wanted: %v, got: %v", wanted, batches)
+	}
+}
+
+func TestForEachBatch_Errors(t *testing.T) {
+	assets := []asset{{Network: "eth", Address: "0x1"}, {Network: "bsc", Address: "0x2"}, {Network: "base", Address: "0x3"}}
+	var networks []string
+	err := forEachBatch(assets, func(network string, addresses []string) error {
+		networks = append(networks, network)
+		if network == "eth" {
+			return errors.New("unknown network")
+		}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "eth: unknown network") {
+		t.Fatalf("incorrect error, got: %v", err)
+	}
+	if strings.Join(networks, " ") != "eth bsc base" {
+		t.Fatalf("a failed batch should not stop the others, got: %v", networks)
+	}
+}
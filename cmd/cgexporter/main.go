@@ -0,0 +1,168 @@
+// Command cgexporter exports CoinGecko and GeckoTerminal data as Prometheus metrics.
+//
+// Usage:
+//
+//	cgexporter -config cgexporter.yaml [flags]
+//
+// It refreshes the prices, market caps and volumes of the coins in the config file with SimplePrice, the reserves,
+// volumes and prices of its pools and tokens with GetMultiPools and GetMultiTokensOnOneNetwork, and global market data
+// with GetGlobalCryptocurrencyData, and serves them at /metrics, along with the request metrics of the API clients.
+// See config for the format of the config file.
+//
+// The CoinGecko API key is read from the COINGECKO_API_KEY environment variable, set COINGECKO_PRO=true for Pro API
+// keys. To share the rate limit with other services, point -coingecko-url and -geckoterminal-url at cgproxy, with
+// COINGECKO_PRO=true if cgproxy has a Pro API key.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+	"github.com/bufdata/coingecko-api/metrics"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	// defaultRateLimit is the rate limit of public and demo API keys in calls per minute.
+	defaultRateLimit = 30
+	// defaultProRateLimit is the rate limit of the lowest Pro API plan in calls per minute.
+	defaultProRateLimit = 500
+	// defaultGeckoTerminalRateLimit is the rate limit of GeckoTerminal API in calls per minute.
+	defaultGeckoTerminalRateLimit = 30
+)
+
+// options are the flags and environment variables of cgexporter.
+type options struct {
+	configPath             string
+	listen                 string
+	apiKey                 string
+	pro                    bool
+	coinGeckoURL           string
+	geckoTerminalURL       string
+	rateLimit              int
+	geckoTerminalRateLimit int
+	retries                int
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		slog.Error("failed to run cgexporter", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run parses arguments and serves the metrics until ctx is done.
+func run(ctx context.Context, arguments []string, stderr io.Writer) error {
+	opts, err := parseOptions(arguments, stderr)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	registry := metrics.NewRegistry()
+	cg, gt := newClients(opts, metrics.NewClientMetrics(registry))
+	e := newExporter(registry, cg, gt, cfg)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	server := &http.Server{Addr: opts.listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		_ = e.run(ctx)
+	}()
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("cgexporter is listening", "address", opts.listen)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelShutdown()
+	return server.Shutdown(shutdownCtx)
+}
+
+// newClients returns the API clients of opts instrumented with m.
+func newClients(opts *options, m *metrics.ClientMetrics) (*coingecko.Client, *geckoterminal.Client) {
+	cgOpts := []coingecko.Option{coingecko.WithMiddleware(m.Middleware(), util.Retry(opts.retries, time.Second))}
+	if opts.rateLimit > 0 {
+		cgOpts = append(cgOpts, coingecko.WithRateLimit(opts.rateLimit))
+	}
+	if opts.coinGeckoURL != "" {
+		cgOpts = append(cgOpts, coingecko.WithBaseURL(opts.coinGeckoURL))
+		if opts.pro {
+			cgOpts = append(cgOpts, coingecko.WithPro())
+		}
+	}
+	gtOpts := []geckoterminal.Option{geckoterminal.WithMiddleware(m.Middleware(),
+		util.Retry(opts.retries, time.Second))}
+	if opts.geckoTerminalRateLimit > 0 {
+		gtOpts = append(gtOpts, geckoterminal.WithRateLimit(opts.geckoTerminalRateLimit))
+	}
+	if opts.geckoTerminalURL != "" {
+		gtOpts = append(gtOpts, geckoterminal.WithBaseURL(opts.geckoTerminalURL))
+	}
+	return coingecko.NewCoinGecko(opts.apiKey, opts.pro, nil, cgOpts...), geckoterminal.NewGeckoTerminal(nil, gtOpts...)
+}
+
+// parseOptions parses the flags and environment variables of cgexporter.
+func parseOptions(arguments []string, stderr io.Writer) (*options, error) {
+	opts := &options{apiKey: os.Getenv("COINGECKO_API_KEY")}
+	if pro := os.Getenv("COINGECKO_PRO"); pro != "" {
+		var err error
+		if opts.pro, err = strconv.ParseBool(pro); err != nil {
+			return nil, fmt.Errorf("invalid COINGECKO_PRO %q: %w", pro, err)
+		}
+	}
+
+	fs := flag.NewFlagSet("cgexporter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.configPath, "config", "cgexporter.yaml", "path of the config file")
+	fs.StringVar(&opts.listen, "listen", ":9101", "address to serve /metrics on")
+	fs.StringVar(&opts.coinGeckoURL, "coingecko-url", "", "CoinGecko API base URL, e.g. of cgproxy")
+	fs.StringVar(&opts.geckoTerminalURL, "geckoterminal-url", "", "GeckoTerminal API base URL, e.g. of cgproxy")
+	fs.IntVar(&opts.rateLimit, "rate-limit", defaultRateLimit,
+		fmt.Sprintf("CoinGecko calls per minute, 0 means no limit, %d by default for Pro API keys", defaultProRateLimit))
+	fs.IntVar(&opts.geckoTerminalRateLimit, "gt-rate-limit", defaultGeckoTerminalRateLimit,
+		"GeckoTerminal calls per minute, 0 means no limit")
+	fs.IntVar(&opts.retries, "retries", 2, "retries of requests failed with 429 or 5xx status codes")
+	if err := fs.Parse(arguments); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	rateLimitSet := false
+	fs.Visit(func(f *flag.Flag) { rateLimitSet = rateLimitSet || f.Name == "rate-limit" })
+	if !rateLimitSet && opts.pro && opts.apiKey != "" {
+		opts.rateLimit = defaultProRateLimit
+	}
+	return opts, nil
+}
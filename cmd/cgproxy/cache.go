@@ -0,0 +1,92 @@
+package main
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// response is an upstream response kept by the cache.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// cacheEntry is an element of cache.order.
+type cacheEntry struct {
+	key     string
+	resp    *response
+	expires time.Time
+}
+
+// cache is a TTL cache of responses which evicts the least recently used entry once it holds maxEntries entries.
+type cache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+// newCache returns a cache which keeps responses for ttl, maxEntries <= 0 means no limit.
+func newCache(ttl time.Duration, maxEntries int) *cache {
+	return &cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// get returns the response of key unless it is missing or expired.
+func (c *cache) get(key string) (*response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.resp, true
+}
+
+// add stores the response of key.
+func (c *cache) add(key string, resp *response) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.resp, entry.expires = resp, expires
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, resp: resp, expires: expires})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// len returns the number of entries, including expired ones which are not evicted yet.
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	c := newCache(time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	c.add("a", &response{status: 200, body: []byte("a")})
+	c.add("b", &response{status: 200, body: []byte("b")})
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("a should be cached")
+	}
+	// b is the least recently used entry.
+	c.add("c", &response{status: 200, body: []byte("c")})
+	if _, ok := c.get("b"); ok {
+		t.Fatal("b should be evicted")
+	}
+	if resp, ok := c.get("a"); !ok || string(resp.body) != "a" {
+		t.Fatalf("incorrect entry of a: %v", resp)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.get("a"); ok {
+		t.Fatal("a should be expired")
+	}
+	if c.len() != 1 {
+		t.Fatalf("incorrect length, wanted: 1, got: %d", c.len())
+	}
+
+	disabled := newCache(0, 0)
+	disabled.add("a", &response{status: 200})
+	if _, ok := disabled.get("a"); ok {
+		t.Fatal("a should not be cached when ttl is 0")
+	}
+}
@@ -0,0 +1,146 @@
+// Command cgproxy is a caching proxy of CoinGecko and GeckoTerminal APIs, so that several services share one API key,
+// one cache and one rate limit.
+//
+// It serves the same paths as the APIs, /api/v3/... for CoinGecko and /api/v2/... for GeckoTerminal, and adds the
+// API key itself. Point the clients at it with the base URL option, and WithPro if the proxy has a Pro API key:
+//
+//	cg := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL("http://localhost:8080/api/v3"),
+//		coingecko.WithPro())
+//	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithBaseURL("http://localhost:8080/api/v2"))
+//
+// Usage:
+//
+//	cgproxy [flags]
+//
+// The CoinGecko API key is read from the COINGECKO_API_KEY environment variable, set COINGECKO_PRO=true for Pro API
+// keys. Successful responses are cached for --ttl, concurrent requests of the same resource share one upstream request
+// and upstream requests are limited to --rate-limit and --gt-rate-limit calls per minute. Responses have an X-Cache
+// header of HIT, MISS or SHARED.
+//
+// The proxy has no authentication and adds the API key to every request, so it listens on 127.0.0.1:8080 by default.
+// Only set --listen to an address reachable by other hosts on a trusted network.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	publicAPIEndpoint        = "https://api.coingecko.com/api/v3"
+	proAPIEndpoint           = "https://pro-api.coingecko.com/api/v3"
+	geckoTerminalAPIEndpoint = "https://api.geckoterminal.com/api/v2"
+
+	// defaultRateLimit is the rate limit of public and demo API keys in calls per minute.
+	defaultRateLimit = 30
+	// defaultProRateLimit is the rate limit of the lowest Pro API plan in calls per minute.
+	defaultProRateLimit = 500
+	// defaultGeckoTerminalRateLimit is the rate limit of GeckoTerminal API in calls per minute.
+	defaultGeckoTerminalRateLimit = 30
+
+	// defaultListen is a loopback address, since anyone who can reach the proxy uses its API key.
+	defaultListen = "127.0.0.1:8080"
+)
+
+// config is the configuration of cgproxy.
+type config struct {
+	Listen string
+	APIKey string
+	Pro    bool
+	// CoinGeckoURL and GeckoTerminalURL override the upstream endpoints.
+	CoinGeckoURL     string
+	GeckoTerminalURL string
+	// RateLimit and GeckoTerminalRateLimit are in calls per minute, 0 means no limit.
+	RateLimit              int
+	GeckoTerminalRateLimit int
+	TTL                    time.Duration
+	MaxEntries             int
+	// Timeout limits each upstream request.
+	Timeout time.Duration
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		slog.Error("failed to run cgproxy", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run parses arguments and serves until ctx is done.
+func run(ctx context.Context, arguments []string, stderr io.Writer) error {
+	cfg, err := parseConfig(arguments, stderr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           newProxy(cfg, nil),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("cgproxy is listening", "address", cfg.Listen)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// parseConfig parses the flags and environment variables of cgproxy.
+func parseConfig(arguments []string, stderr io.Writer) (config, error) {
+	cfg := config{APIKey: os.Getenv("COINGECKO_API_KEY")}
+	if pro := os.Getenv("COINGECKO_PRO"); pro != "" {
+		var err error
+		if cfg.Pro, err = strconv.ParseBool(pro); err != nil {
+			return config{}, fmt.Errorf("invalid COINGECKO_PRO %q: %w", pro, err)
+		}
+	}
+
+	fs := flag.NewFlagSet("cgproxy", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.Listen, "listen", defaultListen, "address to listen on, the proxy has no authentication and "+
+		"other addresses share the API key with anyone who can reach them")
+	fs.StringVar(&cfg.CoinGeckoURL, "coingecko-url", "", "CoinGecko API endpoint (default public or Pro API)")
+	fs.StringVar(&cfg.GeckoTerminalURL, "geckoterminal-url", geckoTerminalAPIEndpoint, "GeckoTerminal API endpoint")
+	fs.IntVar(&cfg.RateLimit, "rate-limit", defaultRateLimit,
+		fmt.Sprintf("CoinGecko calls per minute, 0 means no limit, %d by default for Pro API keys", defaultProRateLimit))
+	fs.IntVar(&cfg.GeckoTerminalRateLimit, "gt-rate-limit", defaultGeckoTerminalRateLimit,
+		"GeckoTerminal calls per minute, 0 means no limit")
+	fs.DurationVar(&cfg.TTL, "ttl", 30*time.Second, "how long responses are cached, 0 disables the cache")
+	fs.IntVar(&cfg.MaxEntries, "max-entries", 10000, "maximum number of cached responses, 0 means no limit")
+	fs.DurationVar(&cfg.Timeout, "timeout", time.Minute, "timeout of upstream requests, including rate limiting")
+	if err := fs.Parse(arguments); err != nil {
+		return config{}, err
+	}
+	if fs.NArg() > 0 {
+		return config{}, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	rateLimitSet := false
+	fs.Visit(func(f *flag.Flag) { rateLimitSet = rateLimitSet || f.Name == "rate-limit" })
+	if !rateLimitSet && cfg.Pro && cfg.APIKey != "" {
+		cfg.RateLimit = defaultProRateLimit
+	}
+	return cfg, nil
+}
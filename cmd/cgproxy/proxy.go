@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	// apiKeyHeader is the header of CoinGecko API key.
+	apiKeyHeader = "x-cg-pro-api-key"
+	// cacheHeader tells whether a response is served from the cache (HIT), fetched from upstream (MISS) or shared
+	// with a concurrent request of the same resource (SHARED).
+	cacheHeader = "X-Cache"
+)
+
+// apiKeyParams are the query parameters of API keys, they are dropped from client requests.
+var apiKeyParams = []string{"x_cg_pro_api_key", "x_cg_demo_api_key"}
+
+// forwardedHeaders are the upstream response headers returned to clients, total and per-page are used by paginated
+// CoinGecko APIs.
+var forwardedHeaders = []string{"Content-Type", "Link", "total", "per-page"}
+
+// upstream is an API served under prefix, e.g. /api/v3 for CoinGecko.
+type upstream struct {
+	prefix  string
+	baseURL string
+	apiKey  string
+	limiter *util.RateLimiter
+}
+
+// call is an in-flight upstream request shared by concurrent requests of the same resource.
+type call struct {
+	done chan struct{}
+	resp *response
+	err  error
+}
+
+// proxy forwards GET requests to the upstream of their path. Successful responses are cached, concurrent requests of
+// the same resource share one upstream request and upstream requests are rate limited per upstream.
+type proxy struct {
+	upstreams  []*upstream
+	httpClient *http.Client
+	cache      *cache
+	timeout    time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// newProxy returns a proxy of cfg, httpClient nil means http.DefaultClient.
+func newProxy(cfg config, httpClient *http.Client) *proxy {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	coinGecko := &upstream{prefix: "/api/v3", baseURL: cfg.CoinGeckoURL, apiKey: cfg.APIKey}
+	if coinGecko.baseURL == "" {
+		coinGecko.baseURL = publicAPIEndpoint
+		if cfg.Pro && cfg.APIKey != "" {
+			coinGecko.baseURL = proAPIEndpoint
+		}
+	}
+	geckoTerminal := &upstream{prefix: "/api/v2", baseURL: cfg.GeckoTerminalURL}
+	if geckoTerminal.baseURL == "" {
+		geckoTerminal.baseURL = geckoTerminalAPIEndpoint
+	}
+	if cfg.RateLimit > 0 {
+		coinGecko.limiter = util.NewRateLimiter(cfg.RateLimit)
+	}
+	if cfg.GeckoTerminalRateLimit > 0 {
+		geckoTerminal.limiter = util.NewRateLimiter(cfg.GeckoTerminalRateLimit)
+	}
+	coinGecko.baseURL = strings.TrimRight(coinGecko.baseURL, "/")
+	geckoTerminal.baseURL = strings.TrimRight(geckoTerminal.baseURL, "/")
+	return &proxy{
+		upstreams:  []*upstream{coinGecko, geckoTerminal},
+		httpClient: httpClient,
+		cache:      newCache(cfg.TTL, cfg.MaxEntries),
+		timeout:    cfg.Timeout,
+		calls:      make(map[string]*call),
+	}
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/healthz" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	u := p.upstream(r.URL.Path)
+	if u == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown path %s", r.URL.Path))
+		return
+	}
+
+	query := r.URL.Query()
+	for _, param := range apiKeyParams {
+		query.Del(param)
+	}
+	// Encode sorts the parameters, so the same resource has the same key whatever the order of its parameters.
+	key := r.URL.Path + "?" + query.Encode()
+
+	if resp, ok := p.cache.get(key); ok {
+		writeResponse(w, r, resp, "HIT")
+		return
+	}
+	resp, shared, err := p.do(r.Context(), key, func(ctx context.Context) (*response, error) {
+		return p.fetch(ctx, u, strings.TrimPrefix(key, u.prefix))
+	})
+	if err != nil {
+		slog.Error("failed to fetch upstream", "path", r.URL.Path, "error", err)
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	state := "MISS"
+	if shared {
+		state = "SHARED"
+	}
+	writeResponse(w, r, resp, state)
+}
+
+// upstream returns the upstream of path, or nil if there is none.
+func (p *proxy) upstream(path string) *upstream {
+	for _, u := range p.upstreams {
+		if path == u.prefix || strings.HasPrefix(path, u.prefix+"/") {
+			return u
+		}
+	}
+	return nil
+}
+
+// do calls fn once for concurrent requests of key; shared tells whether the response is of another request.
+//
+// The upstream request is not canceled with the request which starts it, since other requests may wait for it, and
+// its response is cached anyway.
+func (p *proxy) do(ctx context.Context, key string, fn func(context.Context) (*response, error)) (*response, bool,
+	error) {
+	p.mu.Lock()
+	if c, ok := p.calls[key]; ok {
+		p.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.resp, true, c.err
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+	c := &call{done: make(chan struct{})}
+	p.calls[key] = c
+	p.mu.Unlock()
+
+	upstreamCtx := context.WithoutCancel(ctx)
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		upstreamCtx, cancel = context.WithTimeout(upstreamCtx, p.timeout)
+		defer cancel()
+	}
+	c.resp, c.err = fn(upstreamCtx)
+	if c.err == nil && c.resp.status == http.StatusOK {
+		p.cache.add(key, c.resp)
+	}
+
+	p.mu.Lock()
+	delete(p.calls, key)
+	p.mu.Unlock()
+	close(c.done)
+	return c.resp, false, c.err
+}
+
+// fetch sends a request of path, which includes the query string, to u with the server-side API key.
+func (p *proxy) fetch(ctx context.Context, u *upstream, path string) (*response, error) {
+	if u.limiter != nil {
+		if _, err := u.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if u.apiKey != "" {
+		req.Header.Set(apiKeyHeader, u.apiKey)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header)
+	for _, name := range forwardedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+	return &response{status: resp.StatusCode, header: header, body: body}, nil
+}
+
+func writeResponse(w http.ResponseWriter, r *http.Request, resp *response, state string) {
+	for name, values := range resp.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(cacheHeader, state)
+	w.WriteHeader(resp.status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(resp.body)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+func newTestProxy(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	upstream := httptest.NewServer(handler)
+	t.Cleanup(upstream.Close)
+	server := httptest.NewServer(newProxy(config{
+		APIKey:           "server-key",
+		CoinGeckoURL:     upstream.URL + "/api/v3",
+		GeckoTerminalURL: upstream.URL + "/api/v2",
+		TTL:              time.Minute,
+		Timeout:          time.Second,
+	}, nil))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProxy(t *testing.T) {
+	var requests []string
+	var mu sync.Mutex
+	server := newTestProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery+" "+r.Header.Get(apiKeyHeader))
+		mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/ping"):
+			_, _ = w.Write([]byte(`{"gecko_says":"(V3) To the Moon!"}`))
+		case strings.HasSuffix(r.URL.Path, "/exchanges"):
+			w.Header().Set("total", "1")
+			_, _ = w.Write([]byte(`[{"id":"binance"}]`))
+		case strings.HasSuffix(r.URL.Path, "/networks"):
+			_, _ = w.Write([]byte(`{"data":[{"id":"eth","type":"network"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+		}
+	})
+
+	cg := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	for i := 0; i < 2; i++ {
+		if _, err := cg.Ping(context.TODO()); err != nil {
+			t.Fatalf("error should be nil, got: %v", err)
+		}
+	}
+	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithBaseURL(server.URL+"/api/v2"))
+	if _, err := gt.GetNetworks(context.TODO(), 1); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+
+	cases := []struct {
+		name          string
+		method        string
+		path          string
+		wantedStatus  int
+		wantedCache   string
+		wantedTotal   string
+		wantedRequest string
+	}{
+		{name: "cached", path: "/api/v3/ping", wantedStatus: 200, wantedCache: "HIT"},
+		{name: "miss", path: "/api/v3/exchanges?per_page=1&page=1&x_cg_pro_api_key=client-key", wantedStatus: 200,
+			wantedCache: "MISS", wantedTotal: "1", wantedRequest: "/api/v3/exchanges?page=1&per_page=1 server-key"},
+		{name: "sorted parameters", path: "/api/v3/exchanges?page=1&per_page=1", wantedStatus: 200,
+			wantedCache: "HIT", wantedTotal: "1"},
+		{name: "upstream error", path: "/api/v3/missing", wantedStatus: 404, wantedCache: "MISS",
+			wantedRequest: "/api/v3/missing? server-key"},
+		{name: "upstream error is not cached", path: "/api/v3/missing", wantedStatus: 404, wantedCache: "MISS",
+			wantedRequest: "/api/v3/missing? server-key"},
+		{name: "unknown path", path: "/api/v1/ping", wantedStatus: 404},
+		{name: "method not allowed", method: http.MethodPost, path: "/api/v3/ping", wantedStatus: 405},
+		{name: "health", path: "/healthz", wantedStatus: 200},
+	}
+	// the requests of the clients above.
+	wantedRequests := []string{"/api/v3/ping? server-key", "/api/v2/networks?page=1 "}
+	if strings.Join(requests, ",") != strings.Join(wantedRequests, ",") {
+		t.Fatalf("incorrect requests, wanted: %v, got: %v", wantedRequests, requests)
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, err := http.NewRequest(method, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantedStatus {
+				t.Fatalf("incorrect status, wanted: %d, got: %d", tt.wantedStatus, resp.StatusCode)
+			}
+			if resp.Header.Get(cacheHeader) != tt.wantedCache {
+				t.Fatalf("incorrect %s, wanted: %s, got: %s", cacheHeader, tt.wantedCache, resp.Header.Get(cacheHeader))
+			}
+			if resp.Header.Get("total") != tt.wantedTotal {
+				t.Fatalf("incorrect total, wanted: %s, got: %s", tt.wantedTotal, resp.Header.Get("total"))
+			}
+			if strings.Join(requests, ",") != tt.wantedRequest {
+				t.Fatalf("incorrect requests, wanted: %s, got: %v", tt.wantedRequest, requests)
+			}
+		})
+	}
+}
+
+func TestProxy_Coalesce(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server := newTestProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"gecko_says":"(V3) To the Moon!"}`))
+	})
+
+	const n = 5
+	states := make(chan string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(server.URL + "/api/v3/ping")
+			if err != nil {
+				t.Errorf("error should be nil, got: %v", err)
+				return
+			}
+			resp.Body.Close()
+			states <- resp.Header.Get(cacheHeader)
+		}()
+	}
+	// wait until the first request reaches upstream, then give the others time to join it.
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(states)
+
+	if calls.Load() != 1 {
+		t.Fatalf("incorrect upstream calls, wanted: 1, got: %d", calls.Load())
+	}
+	count := map[string]int{}
+	for state := range states {
+		count[state]++
+	}
+	if count["MISS"] != 1 || count["SHARED"] != n-1 {
+		t.Fatalf("incorrect %s headers: %v", cacheHeader, count)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Setenv("COINGECKO_API_KEY", "key")
+	t.Setenv("COINGECKO_PRO", "true")
+	cfg, err := parseConfig([]string{"-ttl", "10s"}, io.Discard)
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if cfg.APIKey != "key" || !cfg.Pro || cfg.RateLimit != defaultProRateLimit || cfg.TTL != 10*time.Second ||
+		cfg.Listen != "127.0.0.1:8080" {
+		t.Fatalf("incorrect config, got: %+v", cfg)
+	}
+	if cfg, err = parseConfig([]string{"-rate-limit", "0"}, io.Discard); err != nil || cfg.RateLimit != 0 {
+		t.Fatalf("incorrect rate limit, got: %d, error: %v", cfg.RateLimit, err)
+	}
+
+	t.Setenv("COINGECKO_PRO", "maybe")
+	if _, err = parseConfig(nil, io.Discard); err == nil {
+		t.Fatal("error should not be nil")
+	}
+}
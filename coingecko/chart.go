@@ -0,0 +1,129 @@
+package coingecko
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ChartPoint is a single timestamped value of a chart series.
+type ChartPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// OHLC is a single candle returned by GetCoinOHLCByCoinID API.
+//
+// Note: CoinGecko uses the close time of the candle as its timestamp.
+type OHLC struct {
+	Time  time.Time `json:"time"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+}
+
+// Time returns the time of the chart item; CoinGecko timestamps are in unix milliseconds.
+func (c ChartItem) Time() time.Time {
+	return time.UnixMilli(int64(c[0])).UTC()
+}
+
+// Value returns the value of the chart item, e.g. price, market cap or volume.
+func (c ChartItem) Value() float64 {
+	return c[1]
+}
+
+// Point converts the chart item into ChartPoint.
+func (c ChartItem) Point() ChartPoint {
+	return ChartPoint{Time: c.Time(), Value: c.Value()}
+}
+
+// Time returns the close time of the candle.
+func (o CoinOHLCResponse) Time() time.Time {
+	return time.UnixMilli(int64(o[0])).UTC()
+}
+
+// Open returns the opening price of the candle.
+func (o CoinOHLCResponse) Open() float64 {
+	return o[1]
+}
+
+// High returns the highest price of the candle.
+func (o CoinOHLCResponse) High() float64 {
+	return o[2]
+}
+
+// Low returns the lowest price of the candle.
+func (o CoinOHLCResponse) Low() float64 {
+	return o[3]
+}
+
+// Close returns the closing price of the candle.
+func (o CoinOHLCResponse) Close() float64 {
+	return o[4]
+}
+
+// OHLC converts the response item into OHLC.
+func (o CoinOHLCResponse) OHLC() OHLC {
+	return OHLC{Time: o.Time(), Open: o.Open(), High: o.High(), Low: o.Low(), Close: o.Close()}
+}
+
+// Time returns the time of the volume chart item.
+func (e ExchangeVolumeChartResponse) Time() (time.Time, error) {
+	return numberToTime(e[0])
+}
+
+// Volume returns the volume in BTC of the volume chart item.
+func (e ExchangeVolumeChartResponse) Volume() (float64, error) {
+	return e[1].Float64()
+}
+
+// Point converts the volume chart item into ChartPoint.
+func (e ExchangeVolumeChartResponse) Point() (ChartPoint, error) {
+	return numbersToPoint(e[0], e[1])
+}
+
+// Time returns the time of the circulating supply chart item.
+func (c CoinsIDCirculatingSupplyChartItem) Time() (time.Time, error) {
+	return numberToTime(c[0])
+}
+
+// CirculatingSupply returns the circulating supply of the chart item.
+func (c CoinsIDCirculatingSupplyChartItem) CirculatingSupply() (float64, error) {
+	return c[1].Float64()
+}
+
+// Point converts the circulating supply chart item into ChartPoint.
+func (c CoinsIDCirculatingSupplyChartItem) Point() (ChartPoint, error) {
+	return numbersToPoint(c[0], c[1])
+}
+
+// ChartPoints converts chart items into ChartPoint slice.
+func ChartPoints(items []ChartItem) []ChartPoint {
+	points := make([]ChartPoint, 0, len(items))
+	for _, item := range items {
+		points = append(points, item.Point())
+	}
+	return points
+}
+
+// numberToTime converts a json number in unix milliseconds into time. CoinGecko may send it as "1700000000000.0",
+// so it is parsed as float.
+func numberToTime(n json.Number) (time.Time, error) {
+	ms, err := n.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(int64(ms)).UTC(), nil
+}
+
+func numbersToPoint(t, v json.Number) (ChartPoint, error) {
+	tm, err := numberToTime(t)
+	if err != nil {
+		return ChartPoint{}, err
+	}
+	value, err := v.Float64()
+	if err != nil {
+		return ChartPoint{}, err
+	}
+	return ChartPoint{Time: tm, Value: value}, nil
+}
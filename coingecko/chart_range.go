@@ -0,0 +1,159 @@
+package coingecko
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// Granularity is the data interval of market chart data, CoinGecko picks it automatically from the length of the
+// requested range.
+type Granularity int
+
+const (
+	// GranularityFiveMinutely is returned for ranges within 1 day.
+	GranularityFiveMinutely Granularity = iota + 1
+	// GranularityHourly is returned for ranges between 1 day and 90 days.
+	GranularityHourly
+	// GranularityDaily is returned for ranges above 90 days.
+	GranularityDaily
+)
+
+// String returns the name of the granularity.
+func (g Granularity) String() string {
+	switch g {
+	case GranularityFiveMinutely:
+		return "5-minutely"
+	case GranularityHourly:
+		return "hourly"
+	case GranularityDaily:
+		return "daily"
+	default:
+		return fmt.Sprintf("Granularity(%d)", int(g))
+	}
+}
+
+// spanLimits returns the minimum(exclusive) and maximum(inclusive) length of a range which produces the
+// granularity, 0 maximum means no limit.
+func (g Granularity) spanLimits() (time.Duration, time.Duration, error) {
+	const day = 24 * time.Hour
+	switch g {
+	case GranularityFiveMinutely:
+		return 0, day, nil
+	case GranularityHourly:
+		return day + time.Hour, 90 * day, nil
+	case GranularityDaily:
+		return 91 * day, 0, nil
+	default:
+		return 0, 0, fmt.Errorf("invalid granularity: %d", int(g))
+	}
+}
+
+// chartWindow is a single request of a split range. Data is requested for [from, to], but only data within
+// [keepFrom, keepTo] is kept; the window is wider than the kept range when it has to be widened to produce the
+// granularity.
+type chartWindow struct {
+	from     time.Time
+	to       time.Time
+	keepFrom time.Time
+	keepTo   time.Time
+}
+
+// splitChartRange splits [from, to] into windows which produce the granularity. Short windows are widened backward,
+// but not before earliest(zero means no limit) if they start after it; they are widened forward from earliest instead.
+func splitChartRange(from, to time.Time, granularity Granularity, earliest time.Time) ([]chartWindow, error) {
+	minSpan, maxSpan, err := granularity.spanLimits()
+	if err != nil {
+		return nil, err
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from should be before to")
+	}
+
+	var windows []chartWindow
+	for start := from; start.Before(to); {
+		end := to
+		if maxSpan > 0 && end.Sub(start) > maxSpan {
+			end = start.Add(maxSpan)
+		}
+		w := chartWindow{from: start, to: end, keepFrom: start, keepTo: end}
+		if end.Sub(start) <= minSpan {
+			w.from = end.Add(-minSpan - time.Hour)
+			if !earliest.IsZero() && w.from.Before(earliest) && !start.Before(earliest) {
+				w.from = earliest
+				w.to = earliest.Add(minSpan + time.Hour)
+			}
+		}
+		windows = append(windows, w)
+		start = end
+	}
+	return windows, nil
+}
+
+// GetCoinMarketChartRangeByCoinIDWithGranularity gets historical market data of [from, to] in the wanted granularity.
+//
+// CoinGecko picks granularity from the length of the range: 5-minutely within 1 day, hourly up to 90 days and daily
+// beyond. This method splits the range into windows which produce the wanted granularity, fetches them one by one
+// (under the rate limit of the client, see WithRateLimit), removes duplicated boundary points and merges them into one
+// response. Windows shorter than the granularity needs are widened, the extra data is dropped; windows of public
+// clients stay within the past 365 days.
+func (c *Client) GetCoinMarketChartRangeByCoinIDWithGranularity(ctx context.Context, id, vsCurrency string, from,
+	to time.Time, granularity Granularity, precision string) (*CoinMarketChartDataResponse, error) {
+	// public windows are not widened beyond the historical data limit, with an hour left for the requests to be sent.
+	var earliest time.Time
+	if !c.isPro {
+		earliest = time.Now().Add(-publicHistoricalDataLimit + time.Hour)
+	}
+	windows, err := splitChartRange(from, to, granularity, earliest)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices, marketCaps, totalVolumes []ChartItem
+	for _, w := range windows {
+		data, err := c.GetCoinMarketChartRangeByCoinIDWithTime(ctx, id, vsCurrency, w.from, w.to, precision)
+		if err != nil {
+			slog.Error("failed to get market chart range window", "from", w.from, "to", w.to, "error", err)
+			return nil, err
+		}
+		prices = append(prices, filterChartItems(data.Prices, w.keepFrom, w.keepTo)...)
+		marketCaps = append(marketCaps, filterChartItems(data.MarketCaps, w.keepFrom, w.keepTo)...)
+		totalVolumes = append(totalVolumes, filterChartItems(data.TotalVolumes, w.keepFrom, w.keepTo)...)
+	}
+
+	return &CoinMarketChartDataResponse{
+		Prices:       mergeChartItems(prices),
+		MarketCaps:   mergeChartItems(marketCaps),
+		TotalVolumes: mergeChartItems(totalVolumes),
+	}, nil
+}
+
+// filterChartItems returns items within [from, to].
+func filterChartItems(items []ChartItem, from, to time.Time) []ChartItem {
+	result := make([]ChartItem, 0, len(items))
+	for _, item := range items {
+		t := item.Time()
+		if t.Before(from) || t.After(to) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// mergeChartItems sorts items by time and removes items with duplicated timestamp.
+func mergeChartItems(items []ChartItem) []ChartItem {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i][0] < items[j][0]
+	})
+	result := make([]ChartItem, 0, len(items))
+	for _, item := range items {
+		if len(result) != 0 && result[len(result)-1][0] == item[0] {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
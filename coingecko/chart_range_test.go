@@ -0,0 +1,176 @@
+package coingecko
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSplitChartRange(t *testing.T) {
+	to := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name          string
+		from          time.Time
+		granularity   Granularity
+		wantedWindows int
+		wantedIsErr   bool
+	}{
+		{
+			name:          "five minutely",
+			from:          to.Add(-50 * time.Hour),
+			granularity:   GranularityFiveMinutely,
+			wantedWindows: 3,
+		},
+		{
+			name:          "hourly",
+			from:          to.AddDate(0, 0, -200),
+			granularity:   GranularityHourly,
+			wantedWindows: 3,
+		},
+		{
+			name:          "daily",
+			from:          to.AddDate(-2, 0, 0),
+			granularity:   GranularityDaily,
+			wantedWindows: 1,
+		},
+		{
+			name:        "invalid granularity",
+			from:        to.Add(-time.Hour),
+			granularity: Granularity(0),
+			wantedIsErr: true,
+		},
+		{
+			name:        "from is after to",
+			from:        to.Add(time.Hour),
+			granularity: GranularityHourly,
+			wantedIsErr: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := splitChartRange(tt.from, to, tt.granularity, time.Time{})
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if len(windows) != tt.wantedWindows {
+				t.Fatalf("incorrect window number, wanted: %d, got: %d", tt.wantedWindows, len(windows))
+			}
+			minSpan, maxSpan, _ := tt.granularity.spanLimits()
+			for _, w := range windows {
+				span := w.to.Sub(w.from)
+				if span <= minSpan || (maxSpan > 0 && span > maxSpan+time.Hour) {
+					t.Fatalf("window %v - %v does not produce %s data", w.from, w.to, tt.granularity)
+				}
+			}
+			if !windows[0].keepFrom.Equal(tt.from) || !windows[len(windows)-1].keepTo.Equal(to) {
+				t.Fatalf("windows do not cover the range: %+v", windows)
+			}
+		})
+	}
+}
+
+func TestSplitChartRange_WidenShortWindow(t *testing.T) {
+	to := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-3 * time.Hour)
+	windows, err := splitChartRange(from, to, GranularityHourly, time.Time{})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(windows) != 1 || !windows[0].keepFrom.Equal(from) || to.Sub(windows[0].from) <= 25*time.Hour {
+		t.Fatalf("short window should be widened, got: %+v", windows)
+	}
+}
+
+func TestSplitChartRange_WidenForwardFromEarliest(t *testing.T) {
+	to := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-3 * time.Hour)
+	earliest := to.Add(-10 * time.Hour)
+	windows, err := splitChartRange(from, to, GranularityHourly, earliest)
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	w := windows[0]
+	if len(windows) != 1 || !w.from.Equal(earliest) || w.to.Sub(w.from) <= 25*time.Hour || !w.keepFrom.Equal(from) ||
+		!w.keepTo.Equal(to) {
+		t.Fatalf("short window should be widened forward from earliest, got: %+v", windows)
+	}
+}
+
+func TestClient_GetCoinMarketChartRangeByCoinIDWithGranularity_Public(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		var data CoinMarketChartDataResponse
+		for ts := from - from%86400; ts <= to; ts += 86400 {
+			data.Prices = append(data.Prices, ChartItem{float64(ts * 1000), float64(ts)})
+		}
+		resp, _ := json.Marshal(data)
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	client := setup(t)
+	client.apiURL = server.URL
+	client.isPro = false
+	// the daily window of the range is widened to 92 days, which would start beyond the past 365 days.
+	from := time.Now().AddDate(0, 0, -364)
+	to := from.AddDate(0, 0, 30)
+	result, err := client.GetCoinMarketChartRangeByCoinIDWithGranularity(context.TODO(), "bitcoin", "usd", from, to,
+		GranularityDaily, "")
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(result.Prices) != 30 {
+		t.Fatalf("incorrect point number, wanted: 30, got: %d", len(result.Prices))
+	}
+}
+
+func TestClient_GetCoinMarketChartRangeByCoinIDWithGranularity(t *testing.T) {
+	// the server returns a point per hour within [from, to], both ends included.
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		var data CoinMarketChartDataResponse
+		for ts := from; ts <= to; ts += 3600 {
+			data.Prices = append(data.Prices, ChartItem{float64(ts * 1000), float64(ts)})
+		}
+		resp, _ := json.Marshal(data)
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	client := setup(t)
+	client.apiURL = server.URL
+	client.isPro = true
+	to := time.Now().Truncate(time.Hour)
+	from := to.AddDate(0, 0, -100)
+	result, err := client.GetCoinMarketChartRangeByCoinIDWithGranularity(context.TODO(), "bitcoin", "usd", from, to,
+		GranularityHourly, "")
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("incorrect call number, wanted: 2, got: %d", calls)
+	}
+	wantedPoints := int(to.Sub(from)/time.Hour) + 1
+	if len(result.Prices) != wantedPoints {
+		t.Fatalf("incorrect point number, wanted: %d, got: %d", wantedPoints, len(result.Prices))
+	}
+	for i := 1; i < len(result.Prices); i++ {
+		if result.Prices[i][0] <= result.Prices[i-1][0] {
+			t.Fatalf("points should be sorted and unique, got %v after %v", result.Prices[i], result.Prices[i-1])
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package coingecko
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChartItem(t *testing.T) {
+	item := ChartItem{1700000000000, 37000.5}
+	wanted := ChartPoint{Time: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), Value: 37000.5}
+	if result := item.Point(); !reflect.DeepEqual(result, wanted) {
+		t.Fatalf("incorrect chart point, wanted result: %+v, got result: %+v", wanted, result)
+	}
+}
+
+func TestCoinOHLCResponse(t *testing.T) {
+	item := CoinOHLCResponse{1700000000000, 1, 4, 0.5, 2}
+	wanted := OHLC{Time: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), Open: 1, High: 4, Low: 0.5, Close: 2}
+	if result := item.OHLC(); !reflect.DeepEqual(result, wanted) {
+		t.Fatalf("incorrect ohlc, wanted result: %+v, got result: %+v", wanted, result)
+	}
+}
+
+func TestExchangeVolumeChartResponse_Point(t *testing.T) {
+	cases := []struct {
+		name         string
+		item         ExchangeVolumeChartResponse
+		wantedIsErr  bool
+		wantedResult ChartPoint
+		wantedErrStr string
+	}{
+		{
+			name:         "success",
+			item:         ExchangeVolumeChartResponse{"1700000000000.0", "306800.0517941023"},
+			wantedIsErr:  false,
+			wantedResult: ChartPoint{Time: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), Value: 306800.0517941023},
+			wantedErrStr: "",
+		},
+		{
+			name:         "invalid time",
+			item:         ExchangeVolumeChartResponse{"abc", "1"},
+			wantedIsErr:  true,
+			wantedResult: ChartPoint{},
+			wantedErrStr: "invalid syntax",
+		},
+		{
+			name:         "invalid volume",
+			item:         ExchangeVolumeChartResponse{"1700000000000", "abc"},
+			wantedIsErr:  true,
+			wantedResult: ChartPoint{},
+			wantedErrStr: "invalid syntax",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.item.Point()
+			if tt.wantedIsErr {
+				if err == nil || !strings.Contains(err.Error(), tt.wantedErrStr) {
+					t.Fatalf("incorrect error, wanted error: %v, got error: %v", tt.wantedErrStr, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("error should be nil, got: %v", err)
+				}
+			}
+			if !reflect.DeepEqual(result, tt.wantedResult) {
+				t.Fatalf("incorrect chart point, wanted result: %+v, got result: %+v", tt.wantedResult, result)
+			}
+		})
+	}
+}
+
+func TestCoinsIDCirculatingSupplyChartItem_Point(t *testing.T) {
+	var data CoinCirculatingSupplyChartResponse
+	if err := json.Unmarshal([]byte(`{"circulating_supply":[[1700000000000,"19546000.0"]]}`), &data); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	result, err := data.CirculatingSupply[0].Point()
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	wanted := ChartPoint{Time: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), Value: 19546000}
+	if !reflect.DeepEqual(result, wanted) {
+		t.Fatalf("incorrect chart point, wanted result: %+v, got result: %+v", wanted, result)
+	}
+}
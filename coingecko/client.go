@@ -3,10 +3,10 @@ package coingecko
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/bufdata/coingecko-api/util"
 )
@@ -15,16 +15,67 @@ import (
 type Client struct {
 	apiURL     string
 	apiKey     string
+	isPro      bool
 	httpClient *http.Client
+	limiter    *util.RateLimiter
+	// middlewares wrap send, the first one is the outermost.
+	middlewares []util.Middleware
+	tracer      util.Tracer
+}
+
+// Option configures optional settings of Client.
+type Option func(*Client)
+
+// WithRateLimit limits the client to callsPerMinute requests per minute; requests beyond the limit wait until they
+// are allowed. By default, requests are not limited.
+func WithRateLimit(callsPerMinute int) Option {
+	return func(c *Client) {
+		c.limiter = util.NewRateLimiter(callsPerMinute)
+	}
+}
+
+// WithBaseURL sends requests to baseURL instead of the public or Pro API endpoint, e.g. a caching proxy like
+// cmd/cgproxy which adds the API key itself. baseURL includes the API version path, e.g. http://localhost:8080/api/v3.
+// Add WithPro if the proxy sends a Pro API key, otherwise the client applies the limits of the public plan.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.apiURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithPro treats the client as a Pro API client although it has no Pro API key, e.g. with WithBaseURL pointing at a
+// proxy which adds a Pro API key. Pro clients query historical data beyond the past 365 days and are not limited to the
+// plan limits of public clients. It does not change the API endpoint.
+func WithPro() Option {
+	return func(c *Client) {
+		c.isPro = true
+	}
+}
+
+// WithMiddleware wraps every request of the client with middlewares, e.g. metrics.ClientMetrics.Middleware or
+// util.Retry. The first middleware is the outermost one, options may be repeated to append more middlewares.
+func WithMiddleware(middlewares ...util.Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithTracer traces every API call of the client with tracer, e.g. an OpenTelemetry tracer adapted by the cgotel
+// module.
+func WithTracer(tracer util.Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
 }
 
 // NewCoinGecko create a new CoinGecko API client.
 //
 // For users with Pro API Key, users should use [https://pro-api.coingecko.com/api/v3/] to make API request.
 // Therefore, you should provide apiKey and set isProAPIKey to true.
-func NewCoinGecko(apiKey string, isProAPIKey bool, httpClient *http.Client) *Client {
+func NewCoinGecko(apiKey string, isProAPIKey bool, httpClient *http.Client, opts ...Option) *Client {
 	var apiURL string
-	if apiKey != "" && isProAPIKey {
+	isPro := apiKey != "" && isProAPIKey
+	if isPro {
 		apiURL = proAPIEndpoint
 	} else {
 		apiURL = publicAPIEndpoint
@@ -35,15 +86,41 @@ func NewCoinGecko(apiKey string, isProAPIKey bool, httpClient *http.Client) *Cli
 	}
 
 	util.GetLogger("CoinGecko")
-	return &Client{
+	c := &Client{
 		apiURL:     apiURL,
 		apiKey:     apiKey,
+		isPro:      isPro,
 		httpClient: httpClient,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// sendReq sends a request of the client method to endpoint, pathTemplate is the path constant of endpoint.
+func (c *Client) sendReq(ctx context.Context, method, pathTemplate, endpoint string) ([]byte, http.Header, error) {
+	call := util.CallInfo{API: apiName, Method: method, PathTemplate: pathTemplate, Endpoint: endpoint}
+	send := util.Chain(c.send, c.middlewares...)
+	if c.tracer == nil {
+		return send(ctx, call)
+	}
+	return util.TraceCall(ctx, c.tracer, call, send)
 }
 
-func (c *Client) sendReq(ctx context.Context, endpoint string) ([]byte, http.Header, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+func (c *Client) send(ctx context.Context, call util.CallInfo) ([]byte, http.Header, error) {
+	if c.limiter != nil {
+		wait, err := c.limiter.Wait(ctx)
+		if trace := util.ContextRequestTrace(ctx); trace != nil && trace.RateLimitWait != nil {
+			trace.RateLimitWait(wait)
+		}
+		if err != nil {
+			slog.Error("failed to wait for rate limiter", "error", err)
+			return nil, nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, call.Endpoint, nil)
 	if err != nil {
 		slog.Error("failed to new request with context", "error", err)
 		return nil, nil, err
@@ -72,8 +149,7 @@ func (c *Client) doAPI(req *http.Request) ([]byte, http.Header, error) {
 			slog.Error("failed to read error response", "error", err)
 			return nil, nil, err
 		}
-		return nil, nil, fmt.Errorf("failed to call %s, status code: %d, error message: %s", req.URL.String(),
-			resp.StatusCode, string(data))
+		return nil, nil, &util.APIError{URL: req.URL.String(), StatusCode: resp.StatusCode, Message: string(data)}
 	}
 
 	buf := &bytes.Buffer{}
@@ -1,9 +1,13 @@
 package coingecko
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/util"
 )
 
 func TestNewCoinGecko(t *testing.T) {
@@ -12,6 +16,7 @@ func TestNewCoinGecko(t *testing.T) {
 		apiKey       string
 		isPro        bool
 		httpClient   *http.Client
+		opts         []Option
 		wantedResult string
 	}{
 		{
@@ -42,10 +47,18 @@ func TestNewCoinGecko(t *testing.T) {
 			httpClient:   nil,
 			wantedResult: publicAPIEndpoint,
 		},
+		{
+			name:         "base url",
+			apiKey:       "",
+			isPro:        false,
+			httpClient:   nil,
+			opts:         []Option{WithBaseURL("http://localhost:8080/api/v3/")},
+			wantedResult: "http://localhost:8080/api/v3",
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			c := NewCoinGecko(tt.apiKey, tt.isPro, tt.httpClient)
+			c := NewCoinGecko(tt.apiKey, tt.isPro, tt.httpClient, tt.opts...)
 			if c.apiURL != tt.wantedResult {
 				t.Fatalf("incorrect api url, wanted url: %s, got url: %s", tt.wantedResult, c.apiURL)
 			}
@@ -62,3 +75,73 @@ func Test_checkAPIKey(t *testing.T) {
 		t.Fatalf("incorrect http header, wanted header: %s", result)
 	}
 }
+
+func TestNewCoinGecko_WithRateLimit(t *testing.T) {
+	c := NewCoinGecko("", false, nil, WithRateLimit(30))
+	if c.limiter == nil {
+		t.Fatal("rate limiter should be set")
+	}
+}
+
+func TestWithPro(t *testing.T) {
+	server := mockHTTPServer(t, "", `{"prices":[[1700000000000,37000]],"market_caps":[],"total_volumes":[]}`)
+	defer server.Close()
+	to := time.Now().UTC()
+	from := to.AddDate(-2, 0, 0)
+
+	cases := []struct {
+		name        string
+		opts        []Option
+		wantedIsErr bool
+	}{
+		{name: "proxy of public api", opts: []Option{WithBaseURL(server.URL)}, wantedIsErr: true},
+		{name: "proxy of pro api", opts: []Option{WithBaseURL(server.URL), WithPro()}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewCoinGecko("", false, nil, tt.opts...)
+			result, err := client.GetCoinMarketChartRangeByCoinIDWithTime(context.TODO(), "bitcoin", "usd", from, to, "")
+			if (err != nil) != tt.wantedIsErr {
+				t.Fatalf("wanted error: %v, got: %v", tt.wantedIsErr, err)
+			}
+			if !tt.wantedIsErr && len(result.Prices) != 1 {
+				t.Fatalf("incorrect result: %+v", result)
+			}
+		})
+	}
+}
+
+type recordingTracer struct {
+	calls   []util.CallInfo
+	results []util.CallResult
+}
+
+func (r *recordingTracer) StartCall(ctx context.Context, call util.CallInfo) (context.Context, util.CallSpan) {
+	r.calls = append(r.calls, call)
+	return ctx, r
+}
+
+func (r *recordingTracer) End(result util.CallResult) {
+	r.results = append(r.results, result)
+}
+
+func TestWithTracer(t *testing.T) {
+	server := mockHTTPServer(t, "", `{"id":"bitcoin"}`)
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewCoinGecko("", false, nil, WithBaseURL(server.URL), WithTracer(tracer))
+	if _, err := client.GetCoinDataByCoinID(context.TODO(), "bitcoin", false, false, false, false, false, false); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(tracer.calls) != 1 || len(tracer.results) != 1 {
+		t.Fatalf("incorrect calls: %v, results: %v", tracer.calls, tracer.results)
+	}
+	call, result := tracer.calls[0], tracer.results[0]
+	if call.API != "coingecko" || call.Method != "GetCoinDataByCoinID" || call.PathTemplate != coinsIDPath {
+		t.Fatalf("incorrect call: %+v", call)
+	}
+	if result.StatusCode != http.StatusOK || result.ResponseSize != len(`{"id":"bitcoin"}`) || result.Err != nil {
+		t.Fatalf("incorrect result: %+v", result)
+	}
+}
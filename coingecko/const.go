@@ -1,11 +1,16 @@
 package coingecko
 
+import "time"
+
 // define public and pro api endpoint
 const (
 	publicAPIEndpoint = "https://api.coingecko.com/api/v3"
 	proAPIEndpoint    = "https://pro-api.coingecko.com/api/v3"
 )
 
+// apiName is the name of CoinGecko API in traces.
+const apiName = "coingecko"
+
 const (
 	applicationJSONHeader = "application/json"
 	totalHeader           = "total"
@@ -101,3 +106,14 @@ const (
 	coinsCirculatingSupplyChartRangePath = "/coins/%s/circulating_supply_chart/range"
 	tokenListAllPath                     = "/token_lists/%s/all.json"
 )
+
+// historical data limits
+const (
+	// public api users can only query historical data within the past 365 days.
+	publicHistoricalDataLimit = 365 * 24 * time.Hour
+	// the date range of exchange volume chart range api has to be within 31 days.
+	exchangeVolumeChartRangeLimit = 31 * 24 * time.Hour
+)
+
+// historyDateLayout is the date format of the date param of coin history api, i.e. dd-mm-yyyy.
+const historyDateLayout = "02-01-2006"
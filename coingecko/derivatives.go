@@ -0,0 +1,84 @@
+package coingecko
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// perpetualContractType is the contract_type of perpetual contracts, the other one is futures.
+const perpetualContractType = "perpetual"
+
+// derivativesTime converts a derivatives timestamp into time. CoinGecko reports them in unix seconds, values which
+// only make sense as milliseconds are treated as such.
+func derivativesTime(ts int64) time.Time {
+	if ts > 1e12 || ts < -1e12 {
+		return time.UnixMilli(ts).UTC()
+	}
+	return time.Unix(ts, 0).UTC()
+}
+
+func parseConverted(values map[string]string, currency string) (float64, error) {
+	value, ok := values[strings.ToLower(currency)]
+	if !ok {
+		return 0, fmt.Errorf("no converted value in %s", currency)
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// IsPerpetual reports whether the ticker is a perpetual contract.
+func (t *DerivativesExchangesTickersItem) IsPerpetual() bool {
+	return t.ContractType == perpetualContractType
+}
+
+// ConvertedVolumeValue returns the 24h volume in currency(btc, eth or usd).
+func (t *DerivativesExchangesTickersItem) ConvertedVolumeValue(currency string) (float64, error) {
+	return parseConverted(t.ConvertedVolume, currency)
+}
+
+// ConvertedLastValue returns the last price in currency(btc, eth or usd).
+func (t *DerivativesExchangesTickersItem) ConvertedLastValue(currency string) (float64, error) {
+	return parseConverted(t.ConvertedLast, currency)
+}
+
+// LastTradedTime returns the time of the last trade.
+func (t *DerivativesExchangesTickersItem) LastTradedTime() time.Time {
+	return derivativesTime(t.LastTraded)
+}
+
+// ExpiredAtTime returns the expiry of the contract, ok is false for contracts without expiry, e.g. perpetuals.
+func (t *DerivativesExchangesTickersItem) ExpiredAtTime() (expiry time.Time, ok bool) {
+	if t.ExpiredAt == nil || *t.ExpiredAt == 0 {
+		return time.Time{}, false
+	}
+	return derivativesTime(*t.ExpiredAt), true
+}
+
+// IsPerpetual reports whether the ticker is a perpetual contract.
+func (d *DerivativesTickersResponse) IsPerpetual() bool {
+	return d.ContractType == perpetualContractType
+}
+
+// PriceValue returns the price of the ticker.
+func (d *DerivativesTickersResponse) PriceValue() (float64, error) {
+	return strconv.ParseFloat(d.Price, 64)
+}
+
+// LastTradedTime returns the time of the last trade.
+func (d *DerivativesTickersResponse) LastTradedTime() time.Time {
+	return derivativesTime(d.LastTradedAt)
+}
+
+// ExpiredAtTime returns the expiry of the contract, ok is false for contracts without expiry, e.g. perpetuals.
+func (d *DerivativesTickersResponse) ExpiredAtTime() (expiry time.Time, ok bool) {
+	if d.ExpiredAt == nil || *d.ExpiredAt == 0 {
+		return time.Time{}, false
+	}
+	return derivativesTime(*d.ExpiredAt), true
+}
+
+// TradeVolume24hBTCValue returns the 24h trade volume of the exchange in BTC.
+func (d *DerivativesExchangesResponse) TradeVolume24hBTCValue() (float64, error) {
+	return strconv.ParseFloat(d.TradeVolume24hBTC, 64)
+}
@@ -0,0 +1,62 @@
+package coingecko
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDerivativesExchangesTickersItem_Accessors(t *testing.T) {
+	expiredAt := int64(1703836800)
+	item := DerivativesExchangesTickersItem{
+		ContractType:    "futures",
+		ConvertedVolume: map[string]string{"usd": "1234.5"},
+		ConvertedLast:   map[string]string{"btc": "bad"},
+		LastTraded:      1700000000,
+		ExpiredAt:       &expiredAt,
+	}
+	if item.IsPerpetual() {
+		t.Fatal("futures should not be perpetual")
+	}
+	if volume, err := item.ConvertedVolumeValue("USD"); err != nil || volume != 1234.5 {
+		t.Fatalf("incorrect volume: %v, error: %v", volume, err)
+	}
+	if _, err := item.ConvertedLastValue("btc"); err == nil {
+		t.Fatal("error should not be nil")
+	}
+	if _, err := item.ConvertedLastValue("eth"); err == nil {
+		t.Fatal("error should not be nil")
+	}
+	if traded := item.LastTradedTime(); !traded.Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("incorrect last traded time: %s", traded)
+	}
+	if expiry, ok := item.ExpiredAtTime(); !ok || !expiry.Equal(time.Date(2023, 12, 29, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("incorrect expiry: %s", expiry)
+	}
+}
+
+func TestDerivativesTickersResponse_Accessors(t *testing.T) {
+	expiredAt := int64(1703836800000)
+	cases := []struct {
+		name         string
+		item         DerivativesTickersResponse
+		wantedExpiry bool
+	}{
+		{name: "perpetual", item: DerivativesTickersResponse{ContractType: "perpetual", Price: "42000.5"}},
+		{name: "futures in milliseconds", item: DerivativesTickersResponse{ContractType: "futures", Price: "42000.5",
+			ExpiredAt: &expiredAt}, wantedExpiry: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if price, err := tt.item.PriceValue(); err != nil || price != 42000.5 {
+				t.Fatalf("incorrect price: %v, error: %v", price, err)
+			}
+			expiry, ok := tt.item.ExpiredAtTime()
+			if ok != tt.wantedExpiry || tt.item.IsPerpetual() == tt.wantedExpiry {
+				t.Fatalf("incorrect expiry: %s", expiry)
+			}
+			if ok && !expiry.Equal(time.Date(2023, 12, 29, 8, 0, 0, 0, time.UTC)) {
+				t.Fatalf("incorrect expiry: %s", expiry)
+			}
+		})
+	}
+}
@@ -44,7 +44,7 @@ func (c *Client) GetCirculatingSupplyChartByCoinID(ctx context.Context, id strin
 
 	path := fmt.Sprintf(coinsCirculatingSupplyChartPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCirculatingSupplyChartByCoinID", coinsCirculatingSupplyChartPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to coins id circulating supply chart api", "error", err)
 		return nil, err
@@ -91,7 +91,8 @@ func (c *Client) GetCirculatingSupplyChartRangeByCoinID(ctx context.Context, id
 
 	path := fmt.Sprintf(coinsCirculatingSupplyChartRangePath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCirculatingSupplyChartRangeByCoinID", coinsCirculatingSupplyChartRangePath,
+		endpoint)
 	if err != nil {
 		slog.Error("failed to send request to coins id circulating supply chart range api", "error", err)
 		return nil, err
@@ -123,7 +124,7 @@ func (c *Client) ListAllTokensByAssetPlatformID(ctx context.Context, assetPlatfo
 
 	path := fmt.Sprintf(tokenListAllPath, assetPlatformID)
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListAllTokensByAssetPlatformID", tokenListAllPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all tokens api", "error", err)
 		return nil, err
@@ -15,7 +15,7 @@ import (
 // Ping checks API server status.
 func (c *Client) Ping(ctx context.Context) (*PingResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, pingPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "Ping", pingPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to ping api", "error", err)
 		return nil, err
@@ -85,7 +85,7 @@ func (c *Client) SimplePrice(ctx context.Context, ids, vsCurrencies []string, in
 	}
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, simplePricePath, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "SimplePrice", simplePricePath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to simple price api", "error", err)
 		return nil, err
@@ -164,7 +164,7 @@ func (c *Client) SimpleTokenPrice(ctx context.Context, id string, contractAddres
 
 	path := fmt.Sprintf(simpleTokenPricePath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "SimpleTokenPrice", simpleTokenPricePath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to simple token price api", "error", err)
 		return nil, err
@@ -183,7 +183,7 @@ func (c *Client) SimpleTokenPrice(ctx context.Context, id string, contractAddres
 // Cache/Update Frequency: every 60 seconds.
 func (c *Client) SimpleSupportedVSCurrencies(ctx context.Context) (*SimpleSupportedVSCurrenciesResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, supportedVsCurrenciesPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "SimpleSupportedVSCurrencies", supportedVsCurrenciesPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to simple supported vs currencies api", "error", err)
 		return nil, err
@@ -212,7 +212,7 @@ func (c *Client) ListCoinsInfo(ctx context.Context, includePlatform bool) (*[]Li
 	params := url.Values{}
 	params.Add("include_platform", strconv.FormatBool(includePlatform))
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, coinsListPath, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListCoinsInfo", coinsListPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list coins info api", "error", err)
 		return nil, err
@@ -296,7 +296,7 @@ func (c *Client) ListCoinsMarketsData(ctx context.Context, vsCurrency string, id
 	}
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, coinsMarketsPath, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListCoinsMarketsData", coinsMarketsPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list coins market data api", "error", err)
 		return nil, err
@@ -362,7 +362,7 @@ func (c *Client) GetCoinDataByCoinID(ctx context.Context, id string, localizatio
 
 	path := fmt.Sprintf(coinsIDPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCoinDataByCoinID", coinsIDPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get coin data api", "error", err)
 		return nil, err
@@ -429,7 +429,7 @@ func (c *Client) GetCoinTickersByCoinID(ctx context.Context, id, exchangeIDs str
 
 	path := fmt.Sprintf(coinsTickersPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, header, err := c.sendReq(ctx, endpoint)
+	resp, header, err := c.sendReq(ctx, "GetCoinTickersByCoinID", coinsTickersPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get coin tickers api", "error", err)
 		return nil, -1, err
@@ -479,7 +479,7 @@ func (c *Client) GetCoinHistoryDataByCoinID(ctx context.Context, id, date string
 
 	path := fmt.Sprintf(coinsHistoryPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCoinHistoryDataByCoinID", coinsHistoryPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get history data api", "error", err)
 		return nil, err
@@ -541,7 +541,7 @@ func (c *Client) GetCoinMarketChartByCoinID(ctx context.Context, id, vsCurrency,
 
 	path := fmt.Sprintf(coinsMarketChartPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCoinMarketChartByCoinID", coinsMarketChartPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get coin market chart api", "error", err)
 		return nil, err
@@ -604,7 +604,7 @@ func (c *Client) GetCoinMarketChartRangeByCoinID(ctx context.Context, id, vsCurr
 
 	path := fmt.Sprintf(coinsMarketChartRangePath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCoinMarketChartRangeByCoinID", coinsMarketChartRangePath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get market chart range api", "error", err)
 		return nil, err
@@ -661,7 +661,7 @@ func (c *Client) GetCoinOHLCByCoinID(ctx context.Context, id, vsCurrency, days,
 
 	path := fmt.Sprintf(coinsOHLCPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCoinOHLCByCoinID", coinsOHLCPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get coin ohlc api", "error", err)
 		return nil, err
@@ -694,7 +694,7 @@ func (c *Client) GetCoinInfoByContractAddress(ctx context.Context, id, contractA
 
 	path := fmt.Sprintf(coinsContractPath, id, contractAddress)
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCoinInfoByContractAddress", coinsContractPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get coin info api", "error", err)
 		return nil, err
@@ -755,7 +755,7 @@ func (c *Client) GetMarketChartByContractAddress(ctx context.Context, id, contra
 
 	path := fmt.Sprintf(coinsContractMarketChartPath, id, contractAddress)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetMarketChartByContractAddress", coinsContractMarketChartPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get market chart api", "error", err)
 		return nil, err
@@ -822,7 +822,7 @@ func (c *Client) GetMarketChartRangeByContractAddress(ctx context.Context, id, c
 
 	path := fmt.Sprintf(coinsContractMarketChartRangePath, id, contractAddress)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetMarketChartRangeByContractAddress", coinsContractMarketChartRangePath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get market chart range api", "error", err)
 		return nil, err
@@ -854,7 +854,7 @@ func (c *Client) ListAllAssetPlatforms(ctx context.Context, filter string) (*[]A
 		endpoint = fmt.Sprintf("%s%s", c.apiURL, assetPlatformsPath)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListAllAssetPlatforms", assetPlatformsPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all asset platforms api", "error", err)
 		return nil, err
@@ -873,7 +873,7 @@ func (c *Client) ListAllAssetPlatforms(ctx context.Context, filter string) (*[]A
 // Cache/Update Frequency: every 5 minutes.
 func (c *Client) ListAllCategories(ctx context.Context) (*[]ListAllCategoriesResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, coinsCategoriesListPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListAllCategories", coinsCategoriesListPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all categories api", "error", err)
 		return nil, err
@@ -902,7 +902,7 @@ func (c *Client) ListAllCategoriesWithMarketData(ctx context.Context, order stri
 	}
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, coinsCategoriesPath, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListAllCategoriesWithMarketData", coinsCategoriesPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all categories with market data api", "error", err)
 		return nil, err
@@ -937,7 +937,7 @@ func (c *Client) ListAllExchanges(ctx context.Context, perPage, page uint) (*[]E
 	params.Add("page", strconv.Itoa(int(page)))
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, exchangesPath, params.Encode())
-	resp, header, err := c.sendReq(ctx, endpoint)
+	resp, header, err := c.sendReq(ctx, "ListAllExchanges", exchangesPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all exchanges api", "error", err)
 		return nil, -1, err
@@ -966,7 +966,7 @@ func (c *Client) ListAllExchanges(ctx context.Context, perPage, page uint) (*[]E
 // Cache/Update Frequency: every 5 minutes.
 func (c *Client) ListAllMarketsInfo(ctx context.Context) (*[]ExchangeMarketsInfoResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, exchangesListPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListAllMarketsInfo", exchangesListPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all markets info api", "error", err)
 		return nil, err
@@ -1010,7 +1010,7 @@ func (c *Client) GetExchangeVolumeAndTickersByExchangeID(ctx context.Context, id
 
 	path := fmt.Sprintf(exchangesIDPath, id)
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetExchangeVolumeAndTickersByExchangeID", exchangesIDPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get volume and tickers api", "error", err)
 		return nil, err
@@ -1082,7 +1082,7 @@ func (c *Client) GetExchangeTickersByExchangeID(ctx context.Context, id, coinIDs
 
 	path := fmt.Sprintf(exchangesTickerPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, header, err := c.sendReq(ctx, endpoint)
+	resp, header, err := c.sendReq(ctx, "GetExchangeTickersByExchangeID", exchangesTickerPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get exchange tickers api", "error", err)
 		return nil, -1, err
@@ -1129,7 +1129,7 @@ func (c *Client) GetExchangeVolumeChartByExchangeID(ctx context.Context, id stri
 
 	path := fmt.Sprintf(exchangesVolumeChartPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetExchangeVolumeChartByExchangeID", exchangesVolumeChartPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get volume chart api", "error", err)
 		return nil, err
@@ -1162,7 +1162,7 @@ func (c *Client) ListAllDerivativesTickers(ctx context.Context, includeTickers s
 	}
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, derivativesPath, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListAllDerivativesTickers", derivativesPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all derivatives tickers api", "error", err)
 		return nil, err
@@ -1206,7 +1206,7 @@ func (c *Client) ListAllDerivativesExchanges(ctx context.Context, order string,
 	params.Add("page", strconv.Itoa(int(page)))
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, derivativesExchangesPath, params.Encode())
-	resp, header, err := c.sendReq(ctx, endpoint)
+	resp, header, err := c.sendReq(ctx, "ListAllDerivativesExchanges", derivativesExchangesPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all derivatives exchanges api", "error", err)
 		return nil, -1, err
@@ -1264,7 +1264,7 @@ func (c *Client) ListDerivativesExchangeData(ctx context.Context, id, includeTic
 	} else {
 		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListDerivativesExchangeData", derivativesIDPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list derivatives exchange data api", "error", err)
 		return nil, err
@@ -1283,7 +1283,7 @@ func (c *Client) ListDerivativesExchangeData(ctx context.Context, id, includeTic
 // Cache/Update Frequency: every 5 minutes.
 func (c *Client) ListAllDerivativeExchangeInfo(ctx context.Context) (*[]DerivativesExchangeInfoResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, derivativesListPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListAllDerivativeExchangeInfo", derivativesListPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all derivative exchange info api", "error", err)
 		return nil, err
@@ -1331,7 +1331,7 @@ func (c *Client) ListAllNFTInfo(ctx context.Context, order, assetPlatformID stri
 	params.Add("page", strconv.Itoa(int(page)))
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, nftsListPath, params.Encode())
-	resp, header, err := c.sendReq(ctx, endpoint)
+	resp, header, err := c.sendReq(ctx, "ListAllNFTInfo", nftsListPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all nft info api", "error", err)
 		return nil, -1, err
@@ -1368,7 +1368,7 @@ func (c *Client) GetNFTDataByNFTID(ctx context.Context, id string) (*NFTDataResp
 
 	path := fmt.Sprintf(nftsIDPath, id)
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetNFTDataByNFTID", nftsIDPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get nft data api", "error", err)
 		return nil, err
@@ -1404,7 +1404,7 @@ func (c *Client) GetNFTDataByAssetPlatformIDAndContractAddress(ctx context.Conte
 
 	path := fmt.Sprintf(nftsContractPath, assetPlatformID, contractAddress)
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetNFTDataByAssetPlatformIDAndContractAddress", nftsContractPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get nft data api", "error", err)
 		return nil, err
@@ -1423,7 +1423,7 @@ func (c *Client) GetNFTDataByAssetPlatformIDAndContractAddress(ctx context.Conte
 // Cache/Update Frequency: every 60 seconds.
 func (c *Client) GetExchangeRates(ctx context.Context) (*ExchangeRatesResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, exchangeRatesPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetExchangeRates", exchangeRatesPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get exchange rates api", "error", err)
 		return nil, err
@@ -1456,7 +1456,7 @@ func (c *Client) Search(ctx context.Context, query string) (*SearchResponse, err
 	} else {
 		endpoint = fmt.Sprintf("%s%s", c.apiURL, searchPath)
 	}
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "Search", searchPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to search api", "error", err)
 		return nil, err
@@ -1476,7 +1476,7 @@ func (c *Client) Search(ctx context.Context, query string) (*SearchResponse, err
 // Cache/Update Frequency: every 10 minutes.
 func (c *Client) SearchTrending(ctx context.Context) (*SearchTrendingResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, trendingPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "SearchTrending", trendingPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to search trending api", "error", err)
 		return nil, err
@@ -1495,7 +1495,7 @@ func (c *Client) SearchTrending(ctx context.Context) (*SearchTrendingResponse, e
 // Cache/Update Frequency: every 10 minutes.
 func (c *Client) GetGlobalCryptocurrencyData(ctx context.Context) (*GlobalCryptocurrencyResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, globalPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetGlobalCryptocurrencyData", globalPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get global cryptocurrency data api", "error", err)
 		return nil, err
@@ -1514,7 +1514,7 @@ func (c *Client) GetGlobalCryptocurrencyData(ctx context.Context) (*GlobalCrypto
 // Cache/Update Frequency: every 60 minutes.
 func (c *Client) GetGlobalTop100DefiData(ctx context.Context) (*GlobalDefiResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, globalDefiPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetGlobalTop100DefiData", globalDefiPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get global top 100 defi data api", "error", err)
 		return nil, err
@@ -1540,7 +1540,7 @@ func (c *Client) GetCompaniesPublicTreasury(ctx context.Context, coinID string)
 
 	path := fmt.Sprintf(companiesPath, coinID)
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetCompaniesPublicTreasury", companiesPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get companies public treasury api", "error", err)
 		return nil, err
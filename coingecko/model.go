@@ -243,9 +243,7 @@ type NFTInfoResponse struct {
 }
 
 // NFTDataResponse returned by NFTsID or GetNFTDataByNFTID API.
-// TODO: FloorPrice24hPercentageChange, MarketCap24hPercentageChange, Volume24hPercentageChange,FloorPrice7dPercentageChange,
-// FloorPrice14dPercentageChange,FloorPrice30dPercentageChange,FloorPrice1yPercentageChange type is NativeCurrencyUSDItem;
-// but in golang cannot unmarshal them into {} when they are empty.
+// The *PercentageChange fields in native currency and USD are not valid when CoinGecko sends them empty.
 type NFTDataResponse struct {
 	ID              string `json:"id"`
 	ContractAddress string `json:"contract_address"`
@@ -255,35 +253,35 @@ type NFTDataResponse struct {
 	Image           struct {
 		Small string `json:"small"`
 	} `json:"image"`
-	Description                                string                `json:"description"`
-	NativeCurrency                             string                `json:"native_currency"`
-	NativeCurrencySymbol                       string                `json:"native_currency_symbol"`
-	FloorPrice                                 NativeCurrencyUSDItem `json:"floor_price"`
-	MarketCap                                  NativeCurrencyUSDItem `json:"market_cap"`
-	Volume24h                                  NativeCurrencyUSDItem `json:"volume_24h"`
-	FloorPriceInUSD24hPercentageChange         float64               `json:"floor_price_in_usd_24h_percentage_change"`
-	FloorPrice24hPercentageChange              *map[string]any       `json:"floor_price_24h_percentage_change"`
-	MarketCap24hPercentageChange               *map[string]any       `json:"market_cap_24h_percentage_change"`
-	Volume24hPercentageChange                  *map[string]any       `json:"volume_24h_percentage_change"`
-	NumberOfUniqueAddresses                    float64               `json:"number_of_unique_addresses"`
-	NumberOfUniqueAddresses24hPercentageChange float64               `json:"number_of_unique_addresses_24h_percentage_change"`
-	VolumeInUSD24hPercentageChange             float64               `json:"volume_in_usd_24h_percentage_change"`
-	TotalSupply                                float64               `json:"total_supply"`
-	OneDaySales                                float64               `json:"one_day_sales"`
-	OneDaySales24hPercentageChange             float64               `json:"one_day_sales_24h_percentage_change"`
-	OneDayAverageSalePrice                     float64               `json:"one_day_average_sale_price"`
-	OneDayAverageSalePrice24hPercentageChange  float64               `json:"one_day_average_sale_price_24h_percentage_change"`
+	Description                                string                          `json:"description"`
+	NativeCurrency                             string                          `json:"native_currency"`
+	NativeCurrencySymbol                       string                          `json:"native_currency_symbol"`
+	FloorPrice                                 NativeCurrencyUSDItem           `json:"floor_price"`
+	MarketCap                                  NativeCurrencyUSDItem           `json:"market_cap"`
+	Volume24h                                  NativeCurrencyUSDItem           `json:"volume_24h"`
+	FloorPriceInUSD24hPercentageChange         float64                         `json:"floor_price_in_usd_24h_percentage_change"`
+	FloorPrice24hPercentageChange              Optional[NativeCurrencyUSDItem] `json:"floor_price_24h_percentage_change"`
+	MarketCap24hPercentageChange               Optional[NativeCurrencyUSDItem] `json:"market_cap_24h_percentage_change"`
+	Volume24hPercentageChange                  Optional[NativeCurrencyUSDItem] `json:"volume_24h_percentage_change"`
+	NumberOfUniqueAddresses                    float64                         `json:"number_of_unique_addresses"`
+	NumberOfUniqueAddresses24hPercentageChange float64                         `json:"number_of_unique_addresses_24h_percentage_change"`
+	VolumeInUSD24hPercentageChange             float64                         `json:"volume_in_usd_24h_percentage_change"`
+	TotalSupply                                float64                         `json:"total_supply"`
+	OneDaySales                                float64                         `json:"one_day_sales"`
+	OneDaySales24hPercentageChange             float64                         `json:"one_day_sales_24h_percentage_change"`
+	OneDayAverageSalePrice                     float64                         `json:"one_day_average_sale_price"`
+	OneDayAverageSalePrice24hPercentageChange  float64                         `json:"one_day_average_sale_price_24h_percentage_change"`
 	Links                                      struct {
 		Homepage *string `json:"homepage"`
 		Twitter  *string `json:"twitter"`
 		Discord  *string `json:"discord"`
 	} `json:"links"`
-	FloorPrice7dPercentageChange  *map[string]any `json:"floor_price_7d_percentage_change"`
-	FloorPrice14dPercentageChange *map[string]any `json:"floor_price_14d_percentage_change"`
-	FloorPrice30dPercentageChange *map[string]any `json:"floor_price_30d_percentage_change"`
-	FloorPrice60dPercentageChange *map[string]any `json:"floor_price_60d_percentage_change"`
-	FloorPrice1yPercentageChange  *map[string]any `json:"floor_price_1y_percentage_change"`
-	Explorers                     []ExplorerItem  `json:"explorers"`
+	FloorPrice7dPercentageChange  Optional[NativeCurrencyUSDItem] `json:"floor_price_7d_percentage_change"`
+	FloorPrice14dPercentageChange Optional[NativeCurrencyUSDItem] `json:"floor_price_14d_percentage_change"`
+	FloorPrice30dPercentageChange Optional[NativeCurrencyUSDItem] `json:"floor_price_30d_percentage_change"`
+	FloorPrice60dPercentageChange Optional[NativeCurrencyUSDItem] `json:"floor_price_60d_percentage_change"`
+	FloorPrice1yPercentageChange  Optional[NativeCurrencyUSDItem] `json:"floor_price_1y_percentage_change"`
+	Explorers                     []ExplorerItem                  `json:"explorers"`
 }
 
 // ExchangeRatesResponse returned by GetExchangeRates API.
@@ -364,6 +362,7 @@ type GlobalMarketCapChartResponse struct {
 }
 
 // NFTsMarketsResponse returned by ListAllNFTsMarketsData API.
+// The *PercentageChange fields in native currency and USD are not valid when CoinGecko sends them empty.
 type NFTsMarketsResponse struct {
 	ID              string `json:"id"`
 	ContractAddress string `json:"contract_address"`
@@ -372,15 +371,18 @@ type NFTsMarketsResponse struct {
 	Image           struct {
 		Small string `json:"small"`
 	} `json:"image"`
-	Description                                string                `json:"description"`
-	NativeCurrency                             string                `json:"native_currency"`
-	FloorPrice                                 NativeCurrencyUSDItem `json:"floor_price"`
-	MarketCap                                  NativeCurrencyUSDItem `json:"market_cap"`
-	Volume24h                                  NativeCurrencyUSDItem `json:"volume_24h"`
-	FloorPriceInUSD24hPercentageChange         float64               `json:"floor_price_in_usd_24h_percentage_change"`
-	NumberOfUniqueAddresses                    float64               `json:"number_of_unique_addresses"`
-	NumberOfUniqueAddresses24hPercentageChange float64               `json:"number_of_unique_addresses_24h_percentage_change"`
-	TotalSupply                                float64               `json:"total_supply"`
+	Description                                string                          `json:"description"`
+	NativeCurrency                             string                          `json:"native_currency"`
+	FloorPrice                                 NativeCurrencyUSDItem           `json:"floor_price"`
+	MarketCap                                  NativeCurrencyUSDItem           `json:"market_cap"`
+	Volume24h                                  NativeCurrencyUSDItem           `json:"volume_24h"`
+	FloorPriceInUSD24hPercentageChange         float64                         `json:"floor_price_in_usd_24h_percentage_change"`
+	FloorPrice24hPercentageChange              Optional[NativeCurrencyUSDItem] `json:"floor_price_24h_percentage_change"`
+	MarketCap24hPercentageChange               Optional[NativeCurrencyUSDItem] `json:"market_cap_24h_percentage_change"`
+	Volume24hPercentageChange                  Optional[NativeCurrencyUSDItem] `json:"volume_24h_percentage_change"`
+	NumberOfUniqueAddresses                    float64                         `json:"number_of_unique_addresses"`
+	NumberOfUniqueAddresses24hPercentageChange float64                         `json:"number_of_unique_addresses_24h_percentage_change"`
+	TotalSupply                                float64                         `json:"total_supply"`
 }
 
 // NFTsIDMarketChartResponse returned by GetMarketChartByNFTID or GetMarketChartByNFTContractAddress API.
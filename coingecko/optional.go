@@ -0,0 +1,70 @@
+package coingecko
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional is a JSON value which CoinGecko may send as null or as an empty object, array or string when it is not
+// available. Valid is false for such values.
+type Optional[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Get returns the value and whether it is available.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Valid
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+	if isEmptyJSON(data) {
+		o.Value, o.Valid = zero, false
+		return nil
+	}
+	value := zero
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value, o.Valid = value, true
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, unavailable values are encoded as null.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Empty values are decoded into zero values instead of failing.
+func (i *NativeCurrencyUSDItem) UnmarshalJSON(data []byte) error {
+	*i = NativeCurrencyUSDItem{}
+	if isEmptyJSON(data) {
+		return nil
+	}
+	// the alias type has no UnmarshalJSON method and is decoded as a plain struct.
+	type item NativeCurrencyUSDItem
+	return json.Unmarshal(data, (*item)(i))
+}
+
+// isEmptyJSON reports whether data is null or an empty object, array or string.
+func isEmptyJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	switch string(data) {
+	case "", "null", `""`:
+		return true
+	}
+	// empty objects and arrays may have whitespace inside, e.g. { }.
+	if len(data) < 2 {
+		return false
+	}
+	first, last := data[0], data[len(data)-1]
+	if !(first == '{' && last == '}') && !(first == '[' && last == ']') {
+		return false
+	}
+	return len(bytes.TrimSpace(data[1:len(data)-1])) == 0
+}
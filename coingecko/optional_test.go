@@ -0,0 +1,86 @@
+package coingecko
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNFTDataResponse_PercentageChange(t *testing.T) {
+	cases := []struct {
+		name        string
+		data        string
+		wanted      Optional[NativeCurrencyUSDItem]
+		wantedIsErr bool
+	}{
+		{name: "populated object", data: `{"floor_price_24h_percentage_change":{"usd":-1.5,"native_currency":2.25}}`,
+			wanted: Optional[NativeCurrencyUSDItem]{Value: NativeCurrencyUSDItem{NativeCurrency: 2.25, USD: -1.5}, Valid: true}},
+		{name: "empty object", data: `{"floor_price_24h_percentage_change":{ }}`},
+		{name: "empty array", data: `{"floor_price_24h_percentage_change":[]}`},
+		{name: "null", data: `{"floor_price_24h_percentage_change":null}`},
+		{name: "missing", data: `{}`},
+		{name: "invalid", data: `{"floor_price_24h_percentage_change":{"usd":"n/a"}}`, wantedIsErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var data NFTDataResponse
+			err := json.Unmarshal([]byte(tt.data), &data)
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if !reflect.DeepEqual(data.FloorPrice24hPercentageChange, tt.wanted) {
+				t.Fatalf("incorrect result, wanted: %+v, got: %+v", tt.wanted, data.FloorPrice24hPercentageChange)
+			}
+		})
+	}
+}
+
+func TestNFTsMarketsResponse_PercentageChange(t *testing.T) {
+	var data []NFTsMarketsResponse
+	err := json.Unmarshal([]byte(`[{"floor_price_24h_percentage_change":{},"market_cap_24h_percentage_change":{"usd":3}}]`),
+		&data)
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if data[0].FloorPrice24hPercentageChange.Valid || data[0].MarketCap24hPercentageChange.Value.USD != 3 {
+		t.Fatalf("incorrect result, got: %+v", data[0])
+	}
+}
+
+func TestIsEmptyJSON(t *testing.T) {
+	cases := map[string]bool{
+		"": true, " null ": true, `""`: true, "{}": true, "{ \n }": true, "[ ]": true,
+		`" "`: false, `{"usd":1}`: false, "0": false, "[0]": false, "{": false,
+	}
+	for data, wanted := range cases {
+		if result := isEmptyJSON([]byte(data)); result != wanted {
+			t.Fatalf("incorrect result of %q, wanted: %v, got: %v", data, wanted, result)
+		}
+	}
+}
+
+func TestOptional_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal([]Optional[float64]{{Value: 1.5, Valid: true}, {}})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if string(data) != `[1.5,null]` {
+		t.Fatalf("incorrect result, got: %s", data)
+	}
+}
+
+func TestNativeCurrencyUSDItem_UnmarshalJSON(t *testing.T) {
+	var data NFTDataResponse
+	if err := json.Unmarshal([]byte(`{"floor_price":[],"market_cap":{"usd":10}}`), &data); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if data.FloorPrice != (NativeCurrencyUSDItem{}) || data.MarketCap.USD != 10 {
+		t.Fatalf("incorrect result, got: %+v, %+v", data.FloorPrice, data.MarketCap)
+	}
+}
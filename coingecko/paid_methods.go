@@ -19,7 +19,7 @@ import (
 // Update frequency: 30 sec.
 func (c *Client) ListLatest200Coins(ctx context.Context) (*[]ListLatest200CoinsResponse, error) {
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, coinsListNewPath)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "ListLatest200Coins", coinsListNewPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list latest 200 coins api", "error", err)
 		return nil, err
@@ -64,7 +64,7 @@ func (c *Client) GetTopGainersLosers(ctx context.Context, vsCurrency, duration,
 	}
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, topGainersLoserPath, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetTopGainersLosers", topGainersLoserPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get top gainers losers api", "error", err)
 		return nil, err
@@ -103,7 +103,7 @@ func (c *Client) GetGlobalMarketCapChartData(ctx context.Context, days, vsCurren
 	}
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, globalMarketCapChartPath, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetGlobalMarketCapChartData", globalMarketCapChartPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to global market cap chart api", "error", err)
 		return nil, err
@@ -161,7 +161,7 @@ func (c *Client) ListAllNFTsMarketsData(ctx context.Context, assetPlatformID, or
 	params.Add("page", strconv.Itoa(int(page)))
 
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, nftsMarketPath, params.Encode())
-	resp, header, err := c.sendReq(ctx, endpoint)
+	resp, header, err := c.sendReq(ctx, "ListAllNFTsMarketsData", nftsMarketPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to list all nft markets api", "error", err)
 		return nil, -1, err
@@ -213,7 +213,7 @@ func (c *Client) GetMarketChartByNFTID(ctx context.Context, id, days string) (*N
 
 	path := fmt.Sprintf(nftsMarketChartPath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetMarketChartByNFTID", nftsMarketChartPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to nfts id market chart api", "error", err)
 		return nil, err
@@ -263,7 +263,7 @@ func (c *Client) GetMarketChartByNFTContractAddress(ctx context.Context, assetPl
 
 	path := fmt.Sprintf(nftsContractMarketChartPath, assetPlatformID, contractAddress)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetMarketChartByNFTContractAddress", nftsContractMarketChartPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to nfts contract market chart api", "error", err)
 		return nil, err
@@ -294,7 +294,7 @@ func (c *Client) GetNFTTickersByNFTID(ctx context.Context, id string) (*NFTTicke
 
 	path := fmt.Sprintf(nftsTickersPath, id)
 	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetNFTTickersByNFTID", nftsTickersPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to nfts id tickers api", "error", err)
 		return nil, err
@@ -344,7 +344,7 @@ func (c *Client) GetVolumeChartRangeByExchangeID(ctx context.Context, id string,
 
 	path := fmt.Sprintf(exchangeVolumeChartRangePath, id)
 	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetVolumeChartRangeByExchangeID", exchangeVolumeChartRangePath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to exchanges id volume chart range api", "error", err)
 		return nil, err
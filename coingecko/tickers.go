@@ -0,0 +1,147 @@
+package coingecko
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// TickerFilter reports whether a ticker should be kept.
+type TickerFilter func(t *TickersItem) bool
+
+// TickerStats describes the prices of tickers across exchanges in one currency, computed from converted_last and
+// converted_volume.
+type TickerStats struct {
+	Count int `json:"count"`
+	// Volume is the total converted volume.
+	Volume float64 `json:"volume"`
+	// VWAP is the volume-weighted average price, it is the mean price if the total volume is 0.
+	VWAP   float64 `json:"vwap"`
+	Median float64 `json:"median"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	// SpreadPercentage is (Max - Min) / Median in percent.
+	SpreadPercentage float64 `json:"spread_percentage"`
+	// MaxDeviationPercentage is the largest distance of a price from VWAP in percent.
+	MaxDeviationPercentage float64 `json:"max_deviation_percentage"`
+	// BidAskSpreadPercentage is the volume-weighted average of bid_ask_spread_percentage.
+	BidAskSpreadPercentage float64 `json:"bid_ask_spread_percentage"`
+}
+
+// FilterTickers returns the tickers kept by all filters.
+func FilterTickers(tickers []TickersItem, filters ...TickerFilter) []TickersItem {
+	result := make([]TickersItem, 0, len(tickers))
+	for i := range tickers {
+		keep := true
+		for _, filter := range filters {
+			if !filter(&tickers[i]) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, tickers[i])
+		}
+	}
+	return result
+}
+
+// NotStale drops tickers which are not updated from the exchange for more than 8 hours.
+func NotStale(t *TickersItem) bool {
+	return !t.IsStale
+}
+
+// NotAnomaly drops tickers whose price is an outlier.
+func NotAnomaly(t *TickersItem) bool {
+	return !t.IsAnomaly
+}
+
+// Healthy drops stale and anomalous tickers.
+func Healthy(t *TickersItem) bool {
+	return !t.IsStale && !t.IsAnomaly
+}
+
+// WithTrustScore keeps tickers with one of the trust scores, e.g. green or yellow.
+func WithTrustScore(scores ...string) TickerFilter {
+	return func(t *TickersItem) bool {
+		for _, score := range scores {
+			if strings.EqualFold(t.TrustScore, score) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// WithTarget keeps tickers with one of the target currencies, e.g. USDT or USD, case-insensitive.
+func WithTarget(targets ...string) TickerFilter {
+	return func(t *TickersItem) bool {
+		for _, target := range targets {
+			if strings.EqualFold(t.Target, target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// WithMinConvertedVolume keeps tickers whose converted volume in currency(btc, eth or usd) is at least volume.
+// currency is case-insensitive.
+func WithMinConvertedVolume(currency string, volume float64) TickerFilter {
+	currency = strings.ToLower(currency)
+	return func(t *TickersItem) bool {
+		return t.ConvertedVolume[currency] >= volume
+	}
+}
+
+// GetTickerStats computes the price statistics of tickers in currency(btc, eth or usd). Tickers without a positive
+// converted price in currency are skipped. currency is case-insensitive.
+func GetTickerStats(tickers []TickersItem, currency string) (*TickerStats, error) {
+	if currency == "" {
+		return nil, fmt.Errorf("currency should not be empty")
+	}
+	// the API returns lowercase currency keys.
+	currency = strings.ToLower(currency)
+
+	var (
+		prices      []float64
+		stats       TickerStats
+		weighted    float64
+		sum         float64
+		spreadTotal float64
+	)
+	for i := range tickers {
+		price := tickers[i].ConvertedLast[currency]
+		if price <= 0 {
+			continue
+		}
+		volume := max(tickers[i].ConvertedVolume[currency], 0)
+		prices = append(prices, price)
+		sum += price
+		weighted += price * volume
+		stats.Volume += volume
+		spreadTotal += tickers[i].BidAskSpreadPercentage * volume
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("no ticker has converted price in %s", currency)
+	}
+
+	sort.Float64s(prices)
+	stats.Count = len(prices)
+	stats.Min = prices[0]
+	stats.Max = prices[len(prices)-1]
+	stats.Median = prices[len(prices)/2]
+	if len(prices)%2 == 0 {
+		stats.Median = (prices[len(prices)/2-1] + prices[len(prices)/2]) / 2
+	}
+	if stats.Volume > 0 {
+		stats.VWAP = weighted / stats.Volume
+		stats.BidAskSpreadPercentage = spreadTotal / stats.Volume
+	} else {
+		stats.VWAP = sum / float64(len(prices))
+	}
+	stats.SpreadPercentage = (stats.Max - stats.Min) / stats.Median * 100
+	stats.MaxDeviationPercentage = math.Max(stats.Max-stats.VWAP, stats.VWAP-stats.Min) / stats.VWAP * 100
+	return &stats, nil
+}
@@ -0,0 +1,92 @@
+package coingecko
+
+import (
+	"math"
+	"testing"
+)
+
+func ticker(target, trustScore string, price, volume, spread float64, stale, anomaly bool) TickersItem {
+	return TickersItem{
+		Target:                 target,
+		TrustScore:             trustScore,
+		ConvertedLast:          map[string]float64{"usd": price},
+		ConvertedVolume:        map[string]float64{"usd": volume},
+		BidAskSpreadPercentage: spread,
+		IsStale:                stale,
+		IsAnomaly:              anomaly,
+	}
+}
+
+func TestFilterTickers(t *testing.T) {
+	tickers := []TickersItem{
+		ticker("USDT", "green", 100, 1000, 0.1, false, false),
+		ticker("USD", "yellow", 101, 500, 0.2, false, false),
+		ticker("USDT", "green", 90, 100, 0.3, true, false),
+		ticker("EUR", "red", 150, 10, 1, false, true),
+	}
+	cases := []struct {
+		name        string
+		filters     []TickerFilter
+		wantedCount int
+	}{
+		{name: "no filter", wantedCount: 4},
+		{name: "healthy", filters: []TickerFilter{Healthy}, wantedCount: 2},
+		{name: "not stale", filters: []TickerFilter{NotStale}, wantedCount: 3},
+		{name: "not anomaly", filters: []TickerFilter{NotAnomaly}, wantedCount: 3},
+		{name: "trust score", filters: []TickerFilter{WithTrustScore("green")}, wantedCount: 2},
+		{name: "target", filters: []TickerFilter{Healthy, WithTarget("usdt", "eur")}, wantedCount: 1},
+		{name: "min volume", filters: []TickerFilter{WithMinConvertedVolume("usd", 500)}, wantedCount: 2},
+		{name: "min volume upper", filters: []TickerFilter{WithMinConvertedVolume("USD", 500)}, wantedCount: 2},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := FilterTickers(tickers, tt.filters...); len(result) != tt.wantedCount {
+				t.Fatalf("incorrect ticker number, wanted: %d, got: %d", tt.wantedCount, len(result))
+			}
+		})
+	}
+}
+
+func TestGetTickerStats(t *testing.T) {
+	tickers := []TickersItem{
+		ticker("USDT", "green", 100, 3000, 0.1, false, false),
+		ticker("USD", "green", 104, 1000, 0.5, false, false),
+		ticker("EUR", "green", 98, 0, 1, false, false),
+		ticker("BTC", "green", 0, 100, 1, false, false),
+	}
+	stats, err := GetTickerStats(tickers, "usd")
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	wanted := TickerStats{
+		Count:                  3,
+		Volume:                 4000,
+		VWAP:                   101,
+		Median:                 100,
+		Min:                    98,
+		Max:                    104,
+		SpreadPercentage:       6,
+		MaxDeviationPercentage: 3 / 101.0 * 100,
+		BidAskSpreadPercentage: 0.2,
+	}
+	got := []float64{stats.Volume, stats.VWAP, stats.Median, stats.Min, stats.Max, stats.SpreadPercentage,
+		stats.MaxDeviationPercentage, stats.BidAskSpreadPercentage}
+	want := []float64{wanted.Volume, wanted.VWAP, wanted.Median, wanted.Min, wanted.Max, wanted.SpreadPercentage,
+		wanted.MaxDeviationPercentage, wanted.BidAskSpreadPercentage}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("incorrect stats, wanted: %+v, got: %+v", wanted, *stats)
+		}
+	}
+	if stats.Count != wanted.Count {
+		t.Fatalf("incorrect count, wanted: %d, got: %d", wanted.Count, stats.Count)
+	}
+
+	if upper, err := GetTickerStats(tickers, "USD"); err != nil || *upper != *stats {
+		t.Fatalf("currency should be case-insensitive, wanted: %+v, got: %+v, %v", *stats, upper, err)
+	}
+
+	if _, err = GetTickerStats(tickers, "jpy"); err == nil {
+		t.Fatal("error should not be nil")
+	}
+}
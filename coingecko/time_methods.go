@@ -0,0 +1,104 @@
+package coingecko
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// GetCoinMarketChartRangeByCoinIDWithTime is the same as GetCoinMarketChartRangeByCoinID, but takes from and to as
+// time.Time.
+//
+// Public api users can only query historical data within the past 365 days, such ranges are rejected before
+// sending request.
+func (c *Client) GetCoinMarketChartRangeByCoinIDWithTime(ctx context.Context, id, vsCurrency string, from, to time.Time,
+	precision string) (*CoinMarketChartDataResponse, error) {
+	if err := c.checkTimeRange(from, to, 0); err != nil {
+		return nil, err
+	}
+	return c.GetCoinMarketChartRangeByCoinID(ctx, id, vsCurrency, formatUnix(from), formatUnix(to), precision)
+}
+
+// GetMarketChartRangeByContractAddressWithTime is the same as GetMarketChartRangeByContractAddress, but takes from and
+// to as time.Time.
+//
+// Public api users can only query historical data within the past 365 days, such ranges are rejected before
+// sending request.
+func (c *Client) GetMarketChartRangeByContractAddressWithTime(ctx context.Context, id, contractAddress, vsCurrency string,
+	from, to time.Time, precision string) (*CoinMarketChartDataResponse, error) {
+	if err := c.checkTimeRange(from, to, 0); err != nil {
+		return nil, err
+	}
+	return c.GetMarketChartRangeByContractAddress(ctx, id, contractAddress, vsCurrency, formatUnix(from), formatUnix(to),
+		precision)
+}
+
+// GetCoinHistoryDataByCoinIDWithTime is the same as GetCoinHistoryDataByCoinID, but takes date as time.Time. The date
+// is converted into UTC and formatted as dd-mm-yyyy.
+//
+// Public api users can only query historical data within the past 365 days, such dates are rejected before
+// sending request.
+func (c *Client) GetCoinHistoryDataByCoinIDWithTime(ctx context.Context, id string, date time.Time, localization bool) (
+	*CoinHistoryDataResponse, error) {
+	if date.IsZero() {
+		return nil, fmt.Errorf("date should not be zero")
+	}
+	if err := c.checkHistoricalDataLimit(date); err != nil {
+		return nil, err
+	}
+	return c.GetCoinHistoryDataByCoinID(ctx, id, date.UTC().Format(historyDateLayout), localization)
+}
+
+// GetVolumeChartRangeByExchangeIDWithTime is the same as GetVolumeChartRangeByExchangeID, but takes from and to as
+// time.Time.
+//
+// The date range has to be within 31 days, otherwise it is rejected before sending request.
+func (c *Client) GetVolumeChartRangeByExchangeIDWithTime(ctx context.Context, id string, from, to time.Time) (
+	*[]ExchangeVolumeChartResponse, error) {
+	if err := c.checkTimeRange(from, to, exchangeVolumeChartRangeLimit); err != nil {
+		return nil, err
+	}
+	return c.GetVolumeChartRangeByExchangeID(ctx, id, from.Unix(), to.Unix())
+}
+
+// GetCirculatingSupplyChartRangeByCoinIDWithTime is the same as GetCirculatingSupplyChartRangeByCoinID, but takes
+// from and to as time.Time.
+func (c *Client) GetCirculatingSupplyChartRangeByCoinIDWithTime(ctx context.Context, id string, from, to time.Time) (
+	*CoinCirculatingSupplyChartResponse, error) {
+	if err := c.checkTimeRange(from, to, 0); err != nil {
+		return nil, err
+	}
+	return c.GetCirculatingSupplyChartRangeByCoinID(ctx, id, from.Unix(), to.Unix())
+}
+
+// checkTimeRange checks from is before to, the range is not longer than maxSpan(0 means no limit) and the range is
+// allowed by user's plan.
+func (c *Client) checkTimeRange(from, to time.Time, maxSpan time.Duration) error {
+	if from.IsZero() {
+		return fmt.Errorf("from should not be zero")
+	}
+	if to.IsZero() {
+		return fmt.Errorf("to should not be zero")
+	}
+	if !from.Before(to) {
+		return fmt.Errorf("from should be before to")
+	}
+	if maxSpan > 0 && to.Sub(from) > maxSpan {
+		return fmt.Errorf("the time range should be within %d days", int(maxSpan.Hours()/24))
+	}
+	return c.checkHistoricalDataLimit(from)
+}
+
+// checkHistoricalDataLimit checks whether t is within the historical data range of user's plan.
+func (c *Client) checkHistoricalDataLimit(t time.Time) error {
+	if !c.isPro && time.Since(t) > publicHistoricalDataLimit {
+		return fmt.Errorf("public api can only query historical data within the past %d days",
+			int(publicHistoricalDataLimit.Hours()/24))
+	}
+	return nil
+}
+
+func formatUnix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,143 @@
+package coingecko
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockQueryHTTPServer returns resp only if the request has the wanted query parameters.
+func mockQueryHTTPServer(t *testing.T, wantedQuery map[string]string, resp string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range wantedQuery {
+			if r.URL.Query().Get(k) != v {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("invalid request params"))
+				return
+			}
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+}
+
+func TestClient_GetCoinMarketChartRangeByCoinIDWithTime(t *testing.T) {
+	to := time.Now().Truncate(time.Second)
+	from := to.Add(-24 * time.Hour)
+	cases := []struct {
+		name         string
+		isPro        bool
+		from         time.Time
+		to           time.Time
+		wantedIsErr  bool
+		wantedErrStr string
+	}{
+		{
+			name:         "success",
+			from:         from,
+			to:           to,
+			wantedIsErr:  false,
+			wantedErrStr: "",
+		},
+		{
+			name:         "zero from",
+			from:         time.Time{},
+			to:           to,
+			wantedIsErr:  true,
+			wantedErrStr: "from should not be zero",
+		},
+		{
+			name:         "from is after to",
+			from:         to,
+			to:           from,
+			wantedIsErr:  true,
+			wantedErrStr: "from should be before to",
+		},
+		{
+			name:         "exceed public api limit",
+			from:         to.AddDate(-2, 0, 0),
+			to:           to,
+			wantedIsErr:  true,
+			wantedErrStr: "public api can only query historical data within the past 365 days",
+		},
+		{
+			name:         "pro api has no limit",
+			isPro:        true,
+			from:         to.AddDate(-2, 0, 0),
+			to:           to,
+			wantedIsErr:  false,
+			wantedErrStr: "",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			query := map[string]string{"from": formatUnix(tt.from), "to": formatUnix(tt.to)}
+			server := mockQueryHTTPServer(t, query, `{"prices":[[1700000000000,37000]],"market_caps":[],"total_volumes":[]}`)
+			defer server.Close()
+			client := setup(t)
+			client.apiURL = server.URL
+			client.isPro = tt.isPro
+			result, err := client.GetCoinMarketChartRangeByCoinIDWithTime(context.TODO(), "bitcoin", "usd", tt.from, tt.to, "")
+			if tt.wantedIsErr {
+				if err == nil || !strings.Contains(err.Error(), tt.wantedErrStr) {
+					t.Fatalf("incorrect error, wanted error: %v, got error: %v", tt.wantedErrStr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if len(result.Prices) != 1 {
+				t.Fatalf("incorrect response, got result: %+v", result)
+			}
+		})
+	}
+}
+
+func TestClient_GetCoinHistoryDataByCoinIDWithTime(t *testing.T) {
+	date := time.Now().AddDate(0, 0, -10)
+	server := mockQueryHTTPServer(t, map[string]string{"date": date.UTC().Format("02-01-2006")},
+		`{"id":"bitcoin","symbol":"btc","name":"Bitcoin"}`)
+	defer server.Close()
+
+	client := setup(t)
+	client.apiURL = server.URL
+	result, err := client.GetCoinHistoryDataByCoinIDWithTime(context.TODO(), "bitcoin", date, false)
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if result.ID != "bitcoin" {
+		t.Fatalf("incorrect response, got result: %+v", result)
+	}
+
+	_, err = client.GetCoinHistoryDataByCoinIDWithTime(context.TODO(), "bitcoin", time.Time{}, false)
+	if err == nil || !strings.Contains(err.Error(), "date should not be zero") {
+		t.Fatalf("incorrect error, got error: %v", err)
+	}
+}
+
+func TestClient_GetVolumeChartRangeByExchangeIDWithTime(t *testing.T) {
+	to := time.Now()
+	client := setup(t)
+	client.isPro = true
+
+	_, err := client.GetVolumeChartRangeByExchangeIDWithTime(context.TODO(), "binance", to.AddDate(0, 0, -32), to)
+	if err == nil || !strings.Contains(err.Error(), "the time range should be within 31 days") {
+		t.Fatalf("incorrect error, got error: %v", err)
+	}
+
+	server := mockQueryHTTPServer(t, map[string]string{"from": formatUnix(to.AddDate(0, 0, -31)), "to": formatUnix(to)},
+		`[[1700000000000.0,"306800.0517941023"]]`)
+	defer server.Close()
+	client.apiURL = server.URL
+	result, err := client.GetVolumeChartRangeByExchangeIDWithTime(context.TODO(), "binance", to.AddDate(0, 0, -31), to)
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(*result) != 1 {
+		t.Fatalf("incorrect response, got result: %+v", result)
+	}
+}
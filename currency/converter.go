@@ -0,0 +1,221 @@
+// Package currency converts amounts between fiat and crypto units using CoinGecko exchange rates.
+package currency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+// DefaultTTL is the default time to live of cached exchange rates. CoinGecko updates exchange rates every 60 seconds.
+const DefaultTTL = time.Minute
+
+// pivotUnits are tried in order when converting a price map into a unit it does not contain.
+var pivotUnits = []string{"btc", "usd", "eth"}
+
+// Converter converts amounts between any two units returned by GetExchangeRates API, cross rates are computed
+// through BTC. Exchange rates and supported vs currencies are cached with a TTL. Converter is safe for concurrent use.
+type Converter struct {
+	client *coingecko.Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	rates  cache[map[string]coingecko.ExchangeRatesItem]
+	quoted cache[map[string]bool]
+}
+
+// cache is a value fetched from CoinGecko, it is guarded by Converter.mu.
+type cache[T any] struct {
+	value   T
+	ok      bool
+	at      time.Time
+	loading chan struct{} // closed when the running fetch is done
+}
+
+// NewConverter creates a Converter, ttl defaults to DefaultTTL if it is not greater than 0.
+func NewConverter(client *coingecko.Client, ttl time.Duration) *Converter {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Converter{client: client, ttl: ttl, now: time.Now}
+}
+
+// Rate returns the amount of to units per one from unit, e.g. Rate(ctx, "eur", "jpy"). Units are case-insensitive.
+func (c *Converter) Rate(ctx context.Context, from, to string) (float64, error) {
+	rates, err := c.loadRates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return rate(rates, from, to)
+}
+
+// Convert converts amount of from units into to units.
+func (c *Converter) Convert(ctx context.Context, amount float64, from, to string) (float64, error) {
+	r, err := c.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * r, nil
+}
+
+// ConvertPrices returns prices in units, e.g. MarketDataItem.CurrentPrice of a coin converted into units CoinGecko
+// doesn't quote directly. Units already in prices are copied as is, the others are converted from the first pivot
+// unit(btc, usd, eth or else the first unit in alphabetical order) found in both prices and exchange rates.
+func (c *Converter) ConvertPrices(ctx context.Context, prices map[string]float64, units ...string) (map[string]float64, error) {
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("prices should not be empty")
+	}
+	rates, err := c.loadRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(units))
+	var pivot string
+	for _, unit := range units {
+		unit = strings.ToLower(unit)
+		if price, ok := prices[unit]; ok {
+			result[unit] = price
+			continue
+		}
+		if pivot == "" {
+			if pivot = pivotUnit(rates, prices); pivot == "" {
+				return nil, fmt.Errorf("prices have no unit with exchange rate")
+			}
+		}
+		r, err := rate(rates, pivot, unit)
+		if err != nil {
+			return nil, err
+		}
+		result[unit] = prices[pivot] * r
+	}
+	return result, nil
+}
+
+// Units returns the units supported by Converter in alphabetical order.
+func (c *Converter) Units(ctx context.Context) ([]string, error) {
+	rates, err := c.loadRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	units := make([]string, 0, len(rates))
+	for unit := range rates {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+	return units, nil
+}
+
+// IsQuoted reports whether unit is returned by SimpleSupportedVSCurrencies API, i.e. CoinGecko quotes prices in unit
+// directly and no conversion is needed.
+func (c *Converter) IsQuoted(ctx context.Context, unit string) (bool, error) {
+	quoted, err := load(ctx, c, &c.quoted, func(ctx context.Context) (map[string]bool, error) {
+		data, err := c.client.SimpleSupportedVSCurrencies(ctx)
+		if err != nil {
+			return nil, err
+		}
+		quoted := make(map[string]bool, len(*data))
+		for _, u := range *data {
+			quoted[strings.ToLower(u)] = true
+		}
+		return quoted, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return quoted[strings.ToLower(unit)], nil
+}
+
+// loadRates returns the cached exchange rates, they are fetched again once expired.
+func (c *Converter) loadRates(ctx context.Context) (map[string]coingecko.ExchangeRatesItem, error) {
+	return load(ctx, c, &c.rates, func(ctx context.Context) (map[string]coingecko.ExchangeRatesItem, error) {
+		data, err := c.client.GetExchangeRates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rates := make(map[string]coingecko.ExchangeRatesItem, len(data.Rates))
+		for unit, item := range data.Rates {
+			rates[strings.ToLower(unit)] = item
+		}
+		return rates, nil
+	})
+}
+
+// load returns the value of cache e, it is fetched again once expired. Concurrent callers wait for the running
+// fetch instead of fetching again, the mutex is not held while fetching.
+func load[T any](ctx context.Context, c *Converter, e *cache[T], fetch func(context.Context) (T, error)) (T, error) {
+	for {
+		c.mu.Lock()
+		if e.ok && c.now().Sub(e.at) < c.ttl {
+			value := e.value
+			c.mu.Unlock()
+			return value, nil
+		}
+		loading := e.loading
+		if loading == nil {
+			done := make(chan struct{})
+			e.loading = done
+			c.mu.Unlock()
+
+			value, err := fetch(ctx)
+			c.mu.Lock()
+			if err == nil {
+				e.value, e.ok, e.at = value, true, c.now()
+			}
+			e.loading = nil
+			c.mu.Unlock()
+			close(done)
+			return value, err
+		}
+		c.mu.Unlock()
+
+		// the other fetch may fail, e.g. if its context is canceled, then try again.
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// rate computes the cross rate through BTC, each exchange rate value is the amount of the unit per one BTC.
+func rate(rates map[string]coingecko.ExchangeRatesItem, from, to string) (float64, error) {
+	fromRate, ok := rates[strings.ToLower(from)]
+	if !ok {
+		return 0, fmt.Errorf("unsupported unit: %s", from)
+	}
+	toRate, ok := rates[strings.ToLower(to)]
+	if !ok {
+		return 0, fmt.Errorf("unsupported unit: %s", to)
+	}
+	if fromRate.Value == 0 {
+		return 0, fmt.Errorf("exchange rate of %s is 0", from)
+	}
+	return toRate.Value / fromRate.Value, nil
+}
+
+func pivotUnit(rates map[string]coingecko.ExchangeRatesItem, prices map[string]float64) string {
+	for _, unit := range pivotUnits {
+		if _, ok := prices[unit]; ok && rates[unit].Value != 0 {
+			return unit
+		}
+	}
+	units := make([]string, 0, len(prices))
+	for unit := range prices {
+		if rates[unit].Value != 0 {
+			units = append(units, unit)
+		}
+	}
+	if len(units) == 0 {
+		return ""
+	}
+	sort.Strings(units)
+	return units[0]
+}
@@ -0,0 +1,133 @@
+package currency
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+const (
+	exchangeRatesResp = `{"rates":{
+"btc":{"name":"Bitcoin","unit":"BTC","value":1,"type":"crypto"},
+"eth":{"name":"Ether","unit":"ETH","value":20,"type":"crypto"},
+"usd":{"name":"US Dollar","unit":"$","value":40000,"type":"fiat"},
+"eur":{"name":"Euro","unit":"€","value":36000,"type":"fiat"},
+"jpy":{"name":"Japanese Yen","unit":"¥","value":6000000,"type":"fiat"}
+}}`
+	supportedVsCurrenciesResp = `["btc","eth","usd","eur"]`
+)
+
+func setup(t *testing.T, calls *atomic.Int32) *Converter {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if strings.HasSuffix(r.URL.Path, "/supported_vs_currencies") {
+			_, _ = w.Write([]byte(supportedVsCurrenciesResp))
+			return
+		}
+		_, _ = w.Write([]byte(exchangeRatesResp))
+	}))
+	t.Cleanup(server.Close)
+
+	client := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	return NewConverter(client, time.Minute)
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func TestConverter_Convert(t *testing.T) {
+	var calls atomic.Int32
+	c := setup(t, &calls)
+	cases := []struct {
+		name         string
+		amount       float64
+		from         string
+		to           string
+		wantedIsErr  bool
+		wantedResult float64
+	}{
+		{name: "btc to usd", amount: 2, from: "btc", to: "usd", wantedResult: 80000},
+		{name: "cross rate", amount: 36, from: "EUR", to: "jpy", wantedResult: 6000},
+		{name: "crypto to fiat", amount: 1, from: "eth", to: "eur", wantedResult: 1800},
+		{name: "unsupported unit", amount: 1, from: "usd", to: "xyz", wantedIsErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := c.Convert(context.TODO(), tt.amount, tt.from, tt.to)
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if !almostEqual(result, tt.wantedResult) {
+				t.Fatalf("incorrect result, wanted: %v, got: %v", tt.wantedResult, result)
+			}
+		})
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("exchange rates should be cached, got %d calls", calls.Load())
+	}
+
+	now := time.Now()
+	c.now = func() time.Time { return now.Add(time.Hour) }
+	if _, err := c.Rate(context.TODO(), "usd", "eur"); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expired exchange rates should be fetched again, got %d calls", calls.Load())
+	}
+}
+
+func TestConverter_ConvertPrices(t *testing.T) {
+	var calls atomic.Int32
+	c := setup(t, &calls)
+	prices := coingecko.AllCurrencies{"usd": 2000, "eur": 1850}
+	result, err := c.ConvertPrices(context.TODO(), prices, "eur", "JPY")
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if result["eur"] != 1850 || !almostEqual(result["jpy"], 300000) {
+		t.Fatalf("incorrect prices, got: %v", result)
+	}
+
+	quoted, err := c.IsQuoted(context.TODO(), "JPY")
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if quoted {
+		t.Fatal("jpy should not be quoted")
+	}
+}
+
+func TestConverter_ConcurrentRate(t *testing.T) {
+	var calls atomic.Int32
+	c := setup(t, &calls)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Rate(context.TODO(), "usd", "eur"); err != nil {
+				t.Errorf("error should be nil, got: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("exchange rates should be fetched once, got %d calls", calls.Load())
+	}
+}
@@ -0,0 +1,226 @@
+// Package derivatives analyzes funding rates, basis and open interest of derivatives tickers.
+package derivatives
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+// Contract is a derivatives ticker normalized from ListAllDerivativesTickers or ListDerivativesExchangeData API.
+// FundingRate and BasisPercentage are in percent as reported by CoinGecko, Expiry is nil for perpetuals.
+type Contract struct {
+	Market          string     `json:"market"`
+	Symbol          string     `json:"symbol"`
+	Base            string     `json:"base"`
+	Perpetual       bool       `json:"perpetual"`
+	Price           float64    `json:"price"`
+	Index           float64    `json:"index"`
+	BasisPercentage float64    `json:"basis_percentage"`
+	FundingRate     float64    `json:"funding_rate"`
+	OpenInterestUSD float64    `json:"open_interest_usd"`
+	Volume24h       float64    `json:"volume_24h"`
+	LastTradedAt    time.Time  `json:"last_traded_at"`
+	Expiry          *time.Time `json:"expiry,omitempty"`
+}
+
+// Funding is the funding of the perpetual contracts of a base asset.
+type Funding struct {
+	Base string `json:"base"`
+	// WeightedRate is weighted by open interest, it is the mean rate if no contract reports open interest.
+	WeightedRate    float64 `json:"weighted_rate"`
+	MeanRate        float64 `json:"mean_rate"`
+	OpenInterestUSD float64 `json:"open_interest_usd"`
+	Contracts       int     `json:"contracts"`
+}
+
+// BasisPoint is the open-interest-weighted basis of the contracts of a base asset with the same expiry. Expiry is
+// nil for perpetuals.
+type BasisPoint struct {
+	Expiry          *time.Time `json:"expiry,omitempty"`
+	Perpetual       bool       `json:"perpetual"`
+	BasisPercentage float64    `json:"basis_percentage"`
+	OpenInterestUSD float64    `json:"open_interest_usd"`
+	Contracts       int        `json:"contracts"`
+}
+
+// expiryTime returns a pointer to expiry if ok, nil otherwise.
+func expiryTime(expiry time.Time, ok bool) *time.Time {
+	if !ok {
+		return nil
+	}
+	return &expiry
+}
+
+// FromTickers normalizes tickers returned by ListAllDerivativesTickers API. The base asset is the index id and
+// tickers with invalid price are skipped.
+func FromTickers(tickers []coingecko.DerivativesTickersResponse) []Contract {
+	contracts := make([]Contract, 0, len(tickers))
+	for i := range tickers {
+		t := &tickers[i]
+		price, err := t.PriceValue()
+		if err != nil {
+			continue
+		}
+		contracts = append(contracts, Contract{
+			Market:          t.Market,
+			Symbol:          t.Symbol,
+			Base:            strings.ToUpper(t.IndexID),
+			Perpetual:       t.IsPerpetual(),
+			Price:           price,
+			Index:           t.Index,
+			BasisPercentage: t.Basis,
+			FundingRate:     t.FundingRate,
+			OpenInterestUSD: t.OpenInterest,
+			Volume24h:       t.Volume24h,
+			LastTradedAt:    t.LastTradedTime(),
+			Expiry:          expiryTime(t.ExpiredAtTime()),
+		})
+	}
+	return contracts
+}
+
+// FromExchangeTickers normalizes the tickers of market returned by ListDerivativesExchangeData API. Volume24h is the
+// converted volume in USD.
+func FromExchangeTickers(market string, tickers []coingecko.DerivativesExchangesTickersItem) []Contract {
+	contracts := make([]Contract, 0, len(tickers))
+	for i := range tickers {
+		t := &tickers[i]
+		volume, _ := t.ConvertedVolumeValue("usd")
+		contracts = append(contracts, Contract{
+			Market:          market,
+			Symbol:          t.Symbol,
+			Base:            strings.ToUpper(t.Base),
+			Perpetual:       t.IsPerpetual(),
+			Price:           t.Last,
+			Index:           t.Index,
+			BasisPercentage: t.IndexBasisPercentage,
+			FundingRate:     t.FundingRate,
+			OpenInterestUSD: t.OpenInterestUSD,
+			Volume24h:       volume,
+			LastTradedAt:    t.LastTradedTime(),
+			Expiry:          expiryTime(t.ExpiredAtTime()),
+		})
+	}
+	return contracts
+}
+
+// FundingByBase aggregates the funding rates of perpetual contracts per base asset, ordered by open interest in
+// descending order.
+func FundingByBase(contracts []Contract) []Funding {
+	type sums struct {
+		Funding
+		weighted float64
+		total    float64
+	}
+	byBase := make(map[string]*sums)
+	for _, c := range contracts {
+		if !c.Perpetual {
+			continue
+		}
+		s, ok := byBase[c.Base]
+		if !ok {
+			s = &sums{Funding: Funding{Base: c.Base}}
+			byBase[c.Base] = s
+		}
+		s.Contracts++
+		s.total += c.FundingRate
+		if c.OpenInterestUSD > 0 {
+			s.OpenInterestUSD += c.OpenInterestUSD
+			s.weighted += c.FundingRate * c.OpenInterestUSD
+		}
+	}
+
+	result := make([]Funding, 0, len(byBase))
+	for _, s := range byBase {
+		s.MeanRate = s.total / float64(s.Contracts)
+		s.WeightedRate = s.MeanRate
+		if s.OpenInterestUSD > 0 {
+			s.WeightedRate = s.weighted / s.OpenInterestUSD
+		}
+		result = append(result, s.Funding)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].OpenInterestUSD != result[j].OpenInterestUSD {
+			return result[i].OpenInterestUSD > result[j].OpenInterestUSD
+		}
+		return result[i].Base < result[j].Base
+	})
+	return result
+}
+
+// BasisCurve returns the basis curve of base asset: the perpetual point first, followed by the futures points in
+// expiry order. Expired futures at now are skipped.
+func BasisCurve(contracts []Contract, base string, now time.Time) []BasisPoint {
+	type sums struct {
+		BasisPoint
+		weighted float64
+		total    float64
+	}
+	byExpiry := make(map[int64]*sums)
+	for _, c := range contracts {
+		if !strings.EqualFold(c.Base, base) || (!c.Perpetual && (c.Expiry == nil || !c.Expiry.After(now))) {
+			continue
+		}
+		key := int64(0)
+		if !c.Perpetual {
+			key = c.Expiry.Unix()
+		}
+		s, ok := byExpiry[key]
+		if !ok {
+			s = &sums{BasisPoint: BasisPoint{Perpetual: c.Perpetual}}
+			if !c.Perpetual {
+				s.Expiry = c.Expiry
+			}
+			byExpiry[key] = s
+		}
+		s.Contracts++
+		s.total += c.BasisPercentage
+		if c.OpenInterestUSD > 0 {
+			s.OpenInterestUSD += c.OpenInterestUSD
+			s.weighted += c.BasisPercentage * c.OpenInterestUSD
+		}
+	}
+
+	curve := make([]BasisPoint, 0, len(byExpiry))
+	for _, s := range byExpiry {
+		s.BasisPercentage = s.total / float64(s.Contracts)
+		if s.OpenInterestUSD > 0 {
+			s.BasisPercentage = s.weighted / s.OpenInterestUSD
+		}
+		curve = append(curve, s.BasisPoint)
+	}
+	sort.Slice(curve, func(i, j int) bool {
+		if curve[i].Perpetual != curve[j].Perpetual {
+			return curve[i].Perpetual
+		}
+		return curve[i].Expiry.Before(*curve[j].Expiry)
+	})
+	return curve
+}
+
+// ExtremeFunding returns up to n perpetual contracts with the highest and the lowest funding rates. Contracts with
+// open interest below minOpenInterestUSD are skipped to filter out illiquid venues.
+func ExtremeFunding(contracts []Contract, n int, minOpenInterestUSD float64) (highest, lowest []Contract) {
+	if n <= 0 {
+		return nil, nil
+	}
+	var perpetuals []Contract
+	for _, c := range contracts {
+		if c.Perpetual && c.OpenInterestUSD >= minOpenInterestUSD {
+			perpetuals = append(perpetuals, c)
+		}
+	}
+	sort.SliceStable(perpetuals, func(i, j int) bool {
+		return perpetuals[i].FundingRate > perpetuals[j].FundingRate
+	})
+
+	n = min(n, len(perpetuals))
+	highest = append(highest, perpetuals[:n]...)
+	for i := len(perpetuals) - 1; i >= len(perpetuals)-n; i-- {
+		lowest = append(lowest, perpetuals[i])
+	}
+	return highest, lowest
+}
@@ -0,0 +1,79 @@
+package derivatives
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+var now = time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+
+func testContracts() []Contract {
+	december := now.AddDate(0, 1, 28)
+	march := now.AddDate(0, 4, 28)
+	old := now.AddDate(0, -1, 0)
+	return []Contract{
+		{Market: "Binance", Base: "BTC", Perpetual: true, FundingRate: 0.01, BasisPercentage: 0.02, OpenInterestUSD: 3000},
+		{Market: "Bybit", Base: "BTC", Perpetual: true, FundingRate: 0.03, BasisPercentage: 0.06, OpenInterestUSD: 1000},
+		{Market: "Tiny", Base: "BTC", Perpetual: true, FundingRate: 0.5, OpenInterestUSD: 1},
+		{Market: "Binance", Base: "ETH", Perpetual: true, FundingRate: -0.02, OpenInterestUSD: 2000},
+		{Market: "Deribit", Base: "BTC", BasisPercentage: 2, OpenInterestUSD: 500, Expiry: &march},
+		{Market: "Deribit", Base: "BTC", BasisPercentage: 1, OpenInterestUSD: 500, Expiry: &december},
+		{Market: "OKX", Base: "BTC", BasisPercentage: 1.5, OpenInterestUSD: 1500, Expiry: &december},
+		{Market: "Old", Base: "BTC", BasisPercentage: 9, OpenInterestUSD: 1500, Expiry: &old},
+	}
+}
+
+func TestFundingByBase(t *testing.T) {
+	funding := FundingByBase(testContracts())
+	if len(funding) != 2 || funding[0].Base != "BTC" || funding[1].Base != "ETH" {
+		t.Fatalf("incorrect funding, got: %+v", funding)
+	}
+	// (0.01 * 3000 + 0.03 * 1000 + 0.5 * 1) / 4001
+	if math.Abs(funding[0].WeightedRate-60.5/4001) > 1e-12 || funding[0].Contracts != 3 {
+		t.Fatalf("incorrect btc funding, got: %+v", funding[0])
+	}
+}
+
+func TestBasisCurve(t *testing.T) {
+	curve := BasisCurve(testContracts(), "btc", now)
+	if len(curve) != 3 || !curve[0].Perpetual || curve[1].Expiry.After(*curve[2].Expiry) {
+		t.Fatalf("incorrect curve, got: %+v", curve)
+	}
+	if math.Abs(curve[1].BasisPercentage-1.375) > 1e-12 || curve[1].Contracts != 2 {
+		t.Fatalf("incorrect december basis, got: %+v", curve[1])
+	}
+}
+
+func TestExtremeFunding(t *testing.T) {
+	highest, lowest := ExtremeFunding(testContracts(), 1, 100)
+	if len(highest) != 1 || highest[0].Market != "Bybit" || len(lowest) != 1 || lowest[0].Base != "ETH" {
+		t.Fatalf("incorrect extremes, highest: %+v, lowest: %+v", highest, lowest)
+	}
+}
+
+func TestFromTickers(t *testing.T) {
+	expiredAt := int64(1703836800)
+	contracts := FromTickers([]coingecko.DerivativesTickersResponse{
+		{Market: "Deribit", Symbol: "BTC-29DEC23", IndexID: "btc", Price: "42000", ContractType: "futures", Basis: 1.2,
+			OpenInterest: 100, LastTradedAt: 1700000000, ExpiredAt: &expiredAt},
+		{Market: "Broken", Price: "n/a"},
+	})
+	expiry := time.Unix(expiredAt, 0).UTC()
+	wanted := []Contract{{
+		Market:          "Deribit",
+		Symbol:          "BTC-29DEC23",
+		Base:            "BTC",
+		Price:           42000,
+		BasisPercentage: 1.2,
+		OpenInterestUSD: 100,
+		LastTradedAt:    time.Unix(1700000000, 0).UTC(),
+		Expiry:          &expiry,
+	}}
+	if !reflect.DeepEqual(contracts, wanted) {
+		t.Fatalf("incorrect contracts, wanted: %+v, got: %+v", wanted, contracts)
+	}
+}
@@ -0,0 +1,221 @@
+package derivatives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	// DefaultInterval is the default interval between snapshots, derivatives endpoints are updated every 30 seconds.
+	DefaultInterval = time.Minute
+
+	exchangesPerPage = 100
+	// maxExchangePages bounds the pages fetched from ListAllDerivativesExchanges API.
+	maxExchangePages = 20
+)
+
+// TickerRow is a derivatives ticker at the time of a snapshot.
+type TickerRow struct {
+	Time time.Time `json:"time"`
+	Contract
+}
+
+// ExchangeRow is a derivatives exchange at the time of a snapshot.
+type ExchangeRow struct {
+	Time              time.Time `json:"time"`
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	OpenInterestBTC   float64   `json:"open_interest_btc"`
+	TradeVolume24hBTC float64   `json:"trade_volume_24h_btc"`
+	PerpetualPairs    int       `json:"perpetual_pairs"`
+	FuturesPairs      int       `json:"futures_pairs"`
+}
+
+// Sink appends the rows of a snapshot to a time series store.
+type Sink interface {
+	WriteTickers(ctx context.Context, rows []TickerRow) error
+	WriteExchanges(ctx context.Context, rows []ExchangeRow) error
+}
+
+// JSONLSink writes one JSON object per row and line, tickers and exchanges go to separate writers.
+type JSONLSink struct {
+	mu        sync.Mutex
+	tickers   io.Writer
+	exchanges io.Writer
+}
+
+// NewJSONLSink creates a JSONLSink, e.g. with files opened in append mode.
+func NewJSONLSink(tickers, exchanges io.Writer) *JSONLSink {
+	return &JSONLSink{tickers: tickers, exchanges: exchanges}
+}
+
+// WriteTickers implements Sink.
+func (s *JSONLSink) WriteTickers(_ context.Context, rows []TickerRow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return util.WriteJSONL(s.tickers, rows)
+}
+
+// WriteExchanges implements Sink.
+func (s *JSONLSink) WriteExchanges(_ context.Context, rows []ExchangeRow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return util.WriteJSONL(s.exchanges, rows)
+}
+
+// CollectorConfig configures Collector.
+type CollectorConfig struct {
+	// Tickers enables snapshotting ListAllDerivativesTickers API.
+	Tickers bool
+	// Exchanges enables snapshotting ListAllDerivativesExchanges API.
+	Exchanges bool
+	// IncludeExpired includes expired tickers.
+	IncludeExpired bool
+	// Dedup skips tickers whose last trade time is unchanged since they were last stored.
+	Dedup bool
+	// Interval is the interval between snapshots. Default value: DefaultInterval.
+	Interval time.Duration
+}
+
+// Collector periodically snapshots derivatives tickers and exchanges into a Sink.
+type Collector struct {
+	client *coingecko.Client
+	sink   Sink
+	cfg    CollectorConfig
+	now    func() time.Time
+
+	mu         sync.Mutex
+	lastTraded map[string]time.Time
+}
+
+// NewCollector creates a Collector.
+func NewCollector(client *coingecko.Client, sink Sink, cfg CollectorConfig) (*Collector, error) {
+	if !cfg.Tickers && !cfg.Exchanges {
+		return nil, fmt.Errorf("at least one of tickers and exchanges should be enabled")
+	}
+	if sink == nil {
+		return nil, fmt.Errorf("sink should not be nil")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	return &Collector{client: client, sink: sink, cfg: cfg, now: time.Now, lastTraded: make(map[string]time.Time)}, nil
+}
+
+// Collect takes one snapshot and writes it to the sink. A failure of tickers doesn't stop exchanges from being
+// collected, all errors are joined.
+func (c *Collector) Collect(ctx context.Context) error {
+	now := c.now().UTC()
+	var errs []error
+	if c.cfg.Tickers {
+		if err := c.collectTickers(ctx, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to collect tickers: %w", err))
+		}
+	}
+	if c.cfg.Exchanges {
+		if err := c.collectExchanges(ctx, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to collect exchanges: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run collects immediately and then every Interval until ctx is done. Failed snapshots are logged and skipped.
+func (c *Collector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Collect(ctx); err != nil {
+			slog.Error("failed to collect derivatives snapshot", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Collector) collectTickers(ctx context.Context, now time.Time) error {
+	includeTickers := "unexpired"
+	if c.cfg.IncludeExpired {
+		includeTickers = "all"
+	}
+	data, err := c.client.ListAllDerivativesTickers(ctx, includeTickers)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	var rows []TickerRow
+	for _, contract := range FromTickers(*data) {
+		key := contract.Market + "|" + contract.Symbol
+		if c.cfg.Dedup {
+			if last, ok := c.lastTraded[key]; ok && last.Equal(contract.LastTradedAt) {
+				continue
+			}
+		}
+		rows = append(rows, TickerRow{Time: now, Contract: contract})
+	}
+	c.mu.Unlock()
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err = c.sink.WriteTickers(ctx, rows); err != nil {
+		slog.Error("failed to write derivatives tickers", "error", err)
+		return err
+	}
+	if c.cfg.Dedup {
+		c.mu.Lock()
+		for _, row := range rows {
+			c.lastTraded[row.Market+"|"+row.Symbol] = row.LastTradedAt
+		}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+func (c *Collector) collectExchanges(ctx context.Context, now time.Time) error {
+	var rows []ExchangeRow
+	for page := uint(1); page <= maxExchangePages; page++ {
+		data, pageCount, err := c.client.ListAllDerivativesExchanges(ctx, "", exchangesPerPage, page)
+		if err != nil {
+			return err
+		}
+		for i := range *data {
+			exchange := &(*data)[i]
+			// a missing volume is stored as 0 rather than dropping the exchange.
+			volume, _ := exchange.TradeVolume24hBTCValue()
+			rows = append(rows, ExchangeRow{
+				Time:              now,
+				ID:                exchange.ID,
+				Name:              exchange.Name,
+				OpenInterestBTC:   exchange.OpenInterestBTC,
+				TradeVolume24hBTC: volume,
+				PerpetualPairs:    exchange.NumberOfPerpetualPairs,
+				FuturesPairs:      exchange.NumberOfFuturesPairs,
+			})
+		}
+		if len(*data) == 0 || int(page) >= pageCount {
+			break
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err := c.sink.WriteExchanges(ctx, rows); err != nil {
+		slog.Error("failed to write derivatives exchanges", "error", err)
+		return err
+	}
+	return nil
+}
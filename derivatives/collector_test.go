@@ -0,0 +1,102 @@
+package derivatives
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+func countLines(t *testing.T, buf *bytes.Buffer) int {
+	t.Helper()
+	var count int
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var row map[string]any
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			t.Fatalf("invalid jsonl line %q: %v", line, err)
+		}
+		count++
+	}
+	return count
+}
+
+func TestCollector_Collect(t *testing.T) {
+	var lastTraded = int64(1700000000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/derivatives/exchanges") {
+			w.Header().Add("total", "2")
+			_, _ = w.Write([]byte(`[{"name":"Binance (Futures)","id":"binance_futures","open_interest_btc":280000,
+"trade_volume_24h_btc":"500000.5","number_of_perpetual_pairs":300,"number_of_futures_pairs":30},
+{"name":"Deribit","id":"deribit","open_interest_btc":100000,"trade_volume_24h_btc":"n/a"}]`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `[{"market":"Binance (Futures)","symbol":"BTCUSDT","index_id":"BTC","price":"42000",
+"contract_type":"perpetual","funding_rate":0.01,"open_interest":1000,"last_traded_at":1700000000},
+{"market":"Deribit","symbol":"BTC-PERPETUAL","index_id":"BTC","price":"42001","contract_type":"perpetual",
+"funding_rate":0.02,"open_interest":500,"last_traded_at":%d}]`, lastTraded)
+	}))
+	defer server.Close()
+	client := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+
+	var tickers, exchanges bytes.Buffer
+	c, err := NewCollector(client, NewJSONLSink(&tickers, &exchanges), CollectorConfig{Tickers: true, Exchanges: true, Dedup: true})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	c.now = func() time.Time { return time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC) }
+
+	if err = c.Collect(context.TODO()); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	lastTraded++
+	if err = c.Collect(context.TODO()); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+
+	// the binance ticker is unchanged in the second snapshot.
+	if n := countLines(t, &tickers); n != 3 {
+		t.Fatalf("incorrect ticker rows, wanted: 3, got: %d", n)
+	}
+	if n := countLines(t, &exchanges); n != 4 {
+		t.Fatalf("incorrect exchange rows, wanted: 4, got: %d", n)
+	}
+	if !strings.Contains(exchanges.String(), `"trade_volume_24h_btc":500000.5`) {
+		t.Fatalf("converted values should be numbers, got: %s", exchanges.String())
+	}
+}
+
+func TestCollector_CollectTickersError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/derivatives/exchanges") {
+			w.Header().Add("total", "1")
+			_, _ = w.Write([]byte(`[{"name":"Deribit","id":"deribit","open_interest_btc":100000}]`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+
+	var tickers, exchanges bytes.Buffer
+	c, err := NewCollector(client, NewJSONLSink(&tickers, &exchanges), CollectorConfig{Tickers: true, Exchanges: true})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if err = c.Collect(context.TODO()); err == nil {
+		t.Fatalf("error of tickers should be returned")
+	}
+	// exchanges are still collected when tickers fail.
+	if n := countLines(t, &exchanges); n != 1 {
+		t.Fatalf("incorrect exchange rows, wanted: 1, got: %d", n)
+	}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVWriter writes rows as CSV with a header of the column names. Timestamps are RFC 3339 times, nil values are empty
+// fields.
+type CSVWriter struct {
+	w       *csv.Writer
+	columns []Column
+	header  bool
+	record  []string
+}
+
+// NewCSVWriter returns a CSVWriter which writes rows of columns to w.
+func NewCSVWriter(w io.Writer, columns []Column) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+}
+
+// Write writes a row, the header is written before the first row.
+func (c *CSVWriter) Write(row []any) error {
+	if err := checkRow(c.columns, row); err != nil {
+		return err
+	}
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	for i, v := range row {
+		c.record[i] = formatCSV(v)
+	}
+	return c.w.Write(c.record)
+}
+
+// Close writes the header if no row was written and flushes buffered rows.
+func (c *CSVWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *CSVWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	for i, column := range c.columns {
+		c.record[i] = column.Name
+	}
+	return c.w.Write(c.record)
+}
+
+func formatCSV(v any) string {
+	switch v := v.(type) {
+	case time.Time:
+		return formatTimestamp(v)
+	case string:
+		return v
+	case float64:
+		if finite(v) == nil {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,134 @@
+// Package export writes historical data of the API clients as flat tables with typed columns, in CSV, JSON Lines or
+// Parquet format.
+//
+// A Writer streams rows of fixed columns, the Write functions of this package turn API responses into rows:
+//
+//	w, err := export.NewWriter(file, export.Parquet, export.MarketChartColumns)
+//	if err != nil {
+//		return err
+//	}
+//	if err = export.WriteMarketChart(w, "bitcoin", "usd", chart); err != nil {
+//		return err
+//	}
+//	return w.Close()
+package export
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Type is the type of a column.
+type Type int
+
+const (
+	// Timestamp values are time.Time, they are written with millisecond precision in UTC.
+	Timestamp Type = iota + 1
+	// String values are string.
+	String
+	// Float64 values are float64.
+	Float64
+	// Int64 values are int64.
+	Int64
+)
+
+func (t Type) String() string {
+	switch t {
+	case Timestamp:
+		return "timestamp"
+	case String:
+		return "string"
+	case Float64:
+		return "float64"
+	case Int64:
+		return "int64"
+	default:
+		return fmt.Sprintf("Type(%d)", int(t))
+	}
+}
+
+// Column is a column of a table.
+type Column struct {
+	Name string
+	Type Type
+	// Optional columns accept nil values, which are written as empty CSV fields, JSON nulls and Parquet nulls.
+	Optional bool
+}
+
+// Writer writes rows of fixed columns.
+type Writer interface {
+	// Write writes a row, its values are in the order of the columns and of the Go types of the column types.
+	Write(row []any) error
+	// Close writes buffered rows and the end of the file, it does not close the underlying io.Writer.
+	Close() error
+}
+
+// Format is an output format.
+type Format string
+
+// Supported formats.
+const (
+	CSV     Format = "csv"
+	JSONL   Format = "jsonl"
+	Parquet Format = "parquet"
+)
+
+// NewWriter returns a Writer of format which writes rows of columns to w.
+func NewWriter(w io.Writer, format Format, columns []Column) (Writer, error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter(w, columns), nil
+	case JSONL:
+		return NewJSONLWriter(w, columns), nil
+	case Parquet:
+		return NewParquetWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("invalid format %q, valid values: csv, jsonl, parquet", format)
+	}
+}
+
+// checkRow checks that the values of row match columns.
+func checkRow(columns []Column, row []any) error {
+	if len(row) != len(columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(row), len(columns))
+	}
+	for i, c := range columns {
+		var ok bool
+		switch row[i].(type) {
+		case nil:
+			ok = c.Optional
+		case time.Time:
+			ok = c.Type == Timestamp
+		case string:
+			ok = c.Type == String
+		case float64:
+			ok = c.Type == Float64
+		case int64:
+			ok = c.Type == Int64
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v of %s column %s", row[i], c.Type, c.Name)
+		}
+	}
+	return nil
+}
+
+// timestampMillis returns t in unix milliseconds.
+func timestampMillis(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+// formatTimestamp formats t as RFC 3339 time in UTC with millisecond precision, trailing zeros are removed.
+func formatTimestamp(t time.Time) string {
+	return time.UnixMilli(t.UnixMilli()).UTC().Format(time.RFC3339Nano)
+}
+
+// finite returns v, or nil if v is NaN or infinite, which CSV and JSON readers disagree on.
+func finite(v float64) any {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return v
+}
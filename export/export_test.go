@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+var testColumns = []Column{
+	{Name: "timestamp", Type: Timestamp},
+	{Name: "coin_id", Type: String},
+	{Name: "price", Type: Float64, Optional: true},
+	{Name: "rank", Type: Int64, Optional: true},
+}
+
+var testTime = time.Date(2023, 11, 14, 22, 13, 20, 123e6, time.UTC)
+
+func writeTestRows(t *testing.T, format Format) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]any{
+		{testTime, "bitcoin", 42000.5, int64(1)},
+		{testTime.Add(time.Hour), "quote\"d, coin", nil, nil},
+		{testTime, "nan", math.NaN(), int64(2)},
+	}
+	for _, row := range rows {
+		if err = w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestCSVWriter(t *testing.T) {
+	got := writeTestRows(t, CSV)
+	want := "timestamp,coin_id,price,rank\n" +
+		"2023-11-14T22:13:20.123Z,bitcoin,42000.5,1\n" +
+		"2023-11-14T23:13:20.123Z,\"quote\"\"d, coin\",,\n" +
+		"2023-11-14T22:13:20.123Z,nan,,2\n"
+	if got != want {
+		t.Fatalf("incorrect csv, wanted: %q, got: %q", want, got)
+	}
+
+	var buf bytes.Buffer
+	if err := NewCSVWriter(&buf, testColumns).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "timestamp,coin_id,price,rank\n" {
+		t.Fatalf("empty csv should have header, got: %q", buf.String())
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	got := writeTestRows(t, JSONL)
+	want := `{"timestamp":"2023-11-14T22:13:20.123Z","coin_id":"bitcoin","price":42000.5,"rank":1}` + "\n" +
+		`{"timestamp":"2023-11-14T23:13:20.123Z","coin_id":"quote\"d, coin","price":null,"rank":null}` + "\n" +
+		`{"timestamp":"2023-11-14T22:13:20.123Z","coin_id":"nan","price":null,"rank":2}` + "\n"
+	if got != want {
+		t.Fatalf("incorrect jsonl, wanted: %q, got: %q", want, got)
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "xlsx", testColumns); err == nil {
+		t.Fatal("invalid format should fail")
+	}
+}
+
+func TestCheckRow(t *testing.T) {
+	var cases = []struct {
+		name        string
+		row         []any
+		wantedIsErr bool
+	}{
+		{name: "valid", row: []any{testTime, "bitcoin", 1.0, int64(1)}},
+		{name: "nil optional", row: []any{testTime, "bitcoin", nil, nil}},
+		{name: "nil required", row: []any{nil, "bitcoin", 1.0, int64(1)}, wantedIsErr: true},
+		{name: "wrong type", row: []any{testTime, "bitcoin", 1, int64(1)}, wantedIsErr: true},
+		{name: "wrong length", row: []any{testTime, "bitcoin"}, wantedIsErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRow(testColumns, tt.row)
+			if (err != nil) != tt.wantedIsErr {
+				t.Fatalf("wanted error: %v, got: %v", tt.wantedIsErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// JSONLWriter writes rows as JSON Lines, one object per row with the keys in the order of the columns. Timestamps are
+// RFC 3339 strings, nil values and non-finite floats are nulls.
+type JSONLWriter struct {
+	w       *bufio.Writer
+	columns []Column
+	keys    [][]byte
+	line    []byte
+}
+
+// NewJSONLWriter returns a JSONLWriter which writes rows of columns to w.
+func NewJSONLWriter(w io.Writer, columns []Column) *JSONLWriter {
+	keys := make([][]byte, len(columns))
+	for i, c := range columns {
+		keys[i], _ = json.Marshal(c.Name)
+	}
+	return &JSONLWriter{w: bufio.NewWriter(w), columns: columns, keys: keys}
+}
+
+// Write writes a row.
+func (j *JSONLWriter) Write(row []any) error {
+	if err := checkRow(j.columns, row); err != nil {
+		return err
+	}
+	line := append(j.line[:0], '{')
+	for i, v := range row {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		line = append(line, j.keys[i]...)
+		line = append(line, ':')
+		line = appendJSON(line, v)
+	}
+	line = append(line, '}', '\n')
+	j.line = line
+	_, err := j.w.Write(line)
+	return err
+}
+
+// Close flushes buffered rows.
+func (j *JSONLWriter) Close() error {
+	return j.w.Flush()
+}
+
+func appendJSON(b []byte, v any) []byte {
+	switch v := v.(type) {
+	case time.Time:
+		return strconv.AppendQuote(b, formatTimestamp(v))
+	case string:
+		s, _ := json.Marshal(v)
+		return append(b, s...)
+	case float64:
+		if finite(v) == nil {
+			return append(b, "null"...)
+		}
+		return strconv.AppendFloat(b, v, 'f', -1, 64)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	default:
+		return append(b, "null"...)
+	}
+}
@@ -0,0 +1,305 @@
+package export
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// parquetRowGroupSize is the number of rows of each row group of ParquetWriter.
+const parquetRowGroupSize = 64 * 1024
+
+// parquetMagic begins and ends Parquet files.
+const parquetMagic = "PAR1"
+
+// Parquet enums, see parquet.thrift of the Parquet format.
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	parquetUTF8            = 0
+	parquetTimestampMillis = 9
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetDataPage     = 0
+	parquetUncompressed = 0
+)
+
+// ParquetWriter writes rows as a Parquet file with a flat schema of the columns. Timestamps are INT64 columns of UTC
+// milliseconds with TIMESTAMP logical type, strings are UTF-8 BYTE_ARRAY columns, floats are DOUBLE columns and
+// optional columns are nullable.
+//
+// Rows are buffered and written in row groups of uncompressed PLAIN encoded pages, the file is complete once Close
+// writes the footer.
+type ParquetWriter struct {
+	w         io.Writer
+	columns   []Column
+	chunks    []parquetChunk
+	rows      int
+	offset    int64
+	rowGroups []parquetRowGroup
+	numRows   int64
+	err       error
+}
+
+// parquetChunk buffers the values of a column of the current row group.
+type parquetChunk struct {
+	// defs are the definition levels of optional columns, 0 for nil and 1 for values.
+	defs []byte
+	// values are the PLAIN encoded values.
+	values []byte
+}
+
+// parquetRowGroup is the metadata of a written row group.
+type parquetRowGroup struct {
+	numRows int64
+	size    int64
+	chunks  []parquetChunkMeta
+}
+
+type parquetChunkMeta struct {
+	offset int64
+	size   int64
+}
+
+// NewParquetWriter returns a ParquetWriter which writes rows of columns to w.
+func NewParquetWriter(w io.Writer, columns []Column) *ParquetWriter {
+	return &ParquetWriter{w: w, columns: columns, chunks: make([]parquetChunk, len(columns))}
+}
+
+// Write buffers a row and writes a row group once it is full.
+func (p *ParquetWriter) Write(row []any) error {
+	if p.err != nil {
+		return p.err
+	}
+	if err := checkRow(p.columns, row); err != nil {
+		return err
+	}
+	for i, v := range row {
+		chunk := &p.chunks[i]
+		if p.columns[i].Optional {
+			if v == nil {
+				chunk.defs = append(chunk.defs, 0)
+				continue
+			}
+			chunk.defs = append(chunk.defs, 1)
+		}
+		switch v := v.(type) {
+		case time.Time:
+			chunk.values = binary.LittleEndian.AppendUint64(chunk.values, uint64(timestampMillis(v)))
+		case string:
+			chunk.values = binary.LittleEndian.AppendUint32(chunk.values, uint32(len(v)))
+			chunk.values = append(chunk.values, v...)
+		case float64:
+			chunk.values = binary.LittleEndian.AppendUint64(chunk.values, math.Float64bits(v))
+		case int64:
+			chunk.values = binary.LittleEndian.AppendUint64(chunk.values, uint64(v))
+		}
+	}
+	p.rows++
+	if p.rows == parquetRowGroupSize {
+		return p.flush()
+	}
+	return nil
+}
+
+// Close writes buffered rows and the footer of the file.
+func (p *ParquetWriter) Close() error {
+	if p.err != nil {
+		return p.err
+	}
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if err := p.writeMagic(); err != nil {
+		return err
+	}
+	meta := p.fileMetaData()
+	meta = binary.LittleEndian.AppendUint32(meta, uint32(len(meta)))
+	meta = append(meta, parquetMagic...)
+	if err := p.write(meta); err != nil {
+		return err
+	}
+	p.err = errors.New("parquet writer is closed")
+	return nil
+}
+
+// flush writes the buffered rows as a row group with a data page per column.
+func (p *ParquetWriter) flush() error {
+	if p.rows == 0 {
+		return nil
+	}
+	if err := p.writeMagic(); err != nil {
+		return err
+	}
+	group := parquetRowGroup{numRows: int64(p.rows), chunks: make([]parquetChunkMeta, len(p.columns))}
+	for i, c := range p.columns {
+		chunk := &p.chunks[i]
+		var data []byte
+		if c.Optional {
+			levels := appendRLE(nil, chunk.defs)
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(levels)))
+			data = append(data, levels...)
+		}
+		data = append(data, chunk.values...)
+
+		header := p.pageHeader(len(data))
+		group.chunks[i] = parquetChunkMeta{offset: p.offset, size: int64(len(header) + len(data))}
+		group.size += group.chunks[i].size
+		if err := p.write(header); err != nil {
+			return err
+		}
+		if err := p.write(data); err != nil {
+			return err
+		}
+		chunk.defs, chunk.values = chunk.defs[:0], chunk.values[:0]
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.numRows += int64(p.rows)
+	p.rows = 0
+	return nil
+}
+
+func (p *ParquetWriter) writeMagic() error {
+	if p.offset > 0 {
+		return nil
+	}
+	return p.write([]byte(parquetMagic))
+}
+
+// write writes b to the underlying writer, errors are sticky since the file is broken.
+func (p *ParquetWriter) write(b []byte) error {
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	if err != nil {
+		p.err = err
+	}
+	return err
+}
+
+// pageHeader encodes the PageHeader of a data page of size bytes with the buffered rows.
+func (p *ParquetWriter) pageHeader(size int) []byte {
+	var t thriftWriter
+	t.structBegin()
+	t.i32(1, parquetDataPage)
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	t.structField(5)
+	t.i32(1, int32(p.rows))
+	t.i32(2, parquetPlain)
+	t.i32(3, parquetRLE)
+	t.i32(4, parquetRLE)
+	t.structEnd()
+	t.structEnd()
+	return t.buf
+}
+
+// fileMetaData encodes the FileMetaData of the footer.
+func (p *ParquetWriter) fileMetaData() []byte {
+	var t thriftWriter
+	t.structBegin()
+	t.i32(1, 1)
+
+	t.list(2, thriftStruct, len(p.columns)+1)
+	t.structBegin()
+	t.string(4, "schema")
+	t.i32(5, int32(len(p.columns)))
+	t.structEnd()
+	for _, c := range p.columns {
+		t.structBegin()
+		t.i32(1, parquetType(c.Type))
+		if c.Optional {
+			t.i32(3, parquetOptional)
+		} else {
+			t.i32(3, parquetRequired)
+		}
+		t.string(4, c.Name)
+		switch c.Type {
+		case Timestamp:
+			t.i32(6, parquetTimestampMillis)
+			t.structField(10)
+			t.structField(8)
+			t.bool(1, true)
+			t.structField(2)
+			t.structField(1)
+			t.structEnd()
+			t.structEnd()
+			t.structEnd()
+			t.structEnd()
+		case String:
+			t.i32(6, parquetUTF8)
+			t.structField(10)
+			t.structField(1)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.structEnd()
+	}
+
+	t.i64(3, p.numRows)
+
+	t.list(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		t.structBegin()
+		t.list(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			c := p.columns[i]
+			t.structBegin()
+			t.i64(2, chunk.offset)
+			t.structField(3)
+			t.i32(1, parquetType(c.Type))
+			t.list(2, thriftI32, 2)
+			t.i32Value(parquetPlain)
+			t.i32Value(parquetRLE)
+			t.list(3, thriftBinary, 1)
+			t.stringValue(c.Name)
+			t.i32(4, parquetUncompressed)
+			t.i64(5, group.numRows)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.i64(2, group.size)
+		t.i64(3, group.numRows)
+		t.structEnd()
+	}
+
+	t.string(6, "github.com/bufdata/coingecko-api/export")
+	t.structEnd()
+	return t.buf
+}
+
+func parquetType(t Type) int32 {
+	switch t {
+	case String:
+		return parquetByteArray
+	case Float64:
+		return parquetDouble
+	default:
+		return parquetInt64
+	}
+}
+
+// appendRLE appends levels of bit width 1 in the RLE/bit-packing hybrid encoding, as runs of repeated values.
+func appendRLE(b []byte, levels []byte) []byte {
+	for start := 0; start < len(levels); {
+		end := start + 1
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		b = binary.AppendUvarint(b, uint64(end-start)<<1)
+		b = append(b, levels[start])
+		start = end
+	}
+	return b
+}
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestThriftWriter(t *testing.T) {
+	var w thriftWriter
+	w.structBegin()
+	w.i32(1, 3)
+	w.string(4, "ab")
+	w.structField(5)
+	w.bool(1, true)
+	w.structEnd()
+	w.i64(21, -1)
+	w.list(22, thriftI32, 2)
+	w.i32Value(0)
+	w.i32Value(3)
+	w.structEnd()
+
+	want := []byte{
+		0x15, 0x06, // field 1 i32, zigzag 3
+		0x38, 0x02, 'a', 'b', // field 4 (delta 3) binary
+		0x1c, 0x11, 0x00, // field 5 struct with field 1 true, stop
+		0x06, 0x2a, 0x01, // field 21 i64 with long form id, zigzag -1
+		0x19, 0x25, 0x00, 0x06, // field 22 list of 2 i32
+		0x00,
+	}
+	if !bytes.Equal(w.buf, want) {
+		t.Fatalf("incorrect encoding, wanted: % x, got: % x", want, w.buf)
+	}
+}
+
+func TestAppendRLE(t *testing.T) {
+	got := appendRLE(nil, []byte{1, 1, 1, 0, 1})
+	want := []byte{0x06, 1, 0x02, 0, 0x02, 1}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("incorrect encoding, wanted: % x, got: % x", want, got)
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf, testColumns)
+	rows := [][]any{
+		{testTime, "bitcoin", 42000.5, int64(1)},
+		{testTime, "ethereum", nil, nil},
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Write([]any{testTime}); err == nil {
+		t.Fatal("invalid row should fail")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(rows[0]); err == nil {
+		t.Fatal("write after close should fail")
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte(parquetMagic)) || !bytes.HasSuffix(data, []byte(parquetMagic)) {
+		t.Fatal("file should begin and end with magic")
+	}
+	footerSize := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-footerSize : len(data)-8]
+	for _, c := range testColumns {
+		if !bytes.Contains(footer, []byte(c.Name)) {
+			t.Fatalf("footer should contain column %s", c.Name)
+		}
+	}
+
+	// The price column chunk holds the definition levels of both rows and the only non-null value.
+	price := binary.LittleEndian.AppendUint32(nil, 4)
+	price = append(price, appendRLE(nil, []byte{1, 0})...)
+	price = binary.LittleEndian.AppendUint64(price, math.Float64bits(42000.5))
+	if !bytes.Contains(data, price) {
+		t.Fatal("file should contain the price page")
+	}
+	strings := binary.LittleEndian.AppendUint32(nil, 7)
+	strings = append(strings, "bitcoin"...)
+	strings = binary.LittleEndian.AppendUint32(strings, 8)
+	strings = append(strings, "ethereum"...)
+	if !bytes.Contains(data, strings) {
+		t.Fatal("file should contain the coin_id page")
+	}
+}
+
+func TestParquetWriterRowGroups(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf, testColumns[:1])
+	for i := 0; i < parquetRowGroupSize+1; i++ {
+		if err := w.Write([]any{testTime}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.rowGroups) != 2 || w.rowGroups[1].numRows != 1 || w.numRows != parquetRowGroupSize+1 {
+		t.Fatalf("incorrect row groups, got: %+v", w.rowGroups)
+	}
+
+	buf.Reset()
+	if err := NewParquetWriter(&buf, testColumns).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte(parquetMagic)) {
+		t.Fatal("empty file should begin with magic")
+	}
+}
@@ -0,0 +1,202 @@
+package export
+
+import (
+	"sort"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+// MarketChartColumns are the columns of WriteMarketChart.
+var MarketChartColumns = []Column{
+	{Name: "timestamp", Type: Timestamp},
+	{Name: "coin_id", Type: String},
+	{Name: "vs_currency", Type: String},
+	{Name: "price", Type: Float64, Optional: true},
+	{Name: "market_cap", Type: Float64, Optional: true},
+	{Name: "total_volume", Type: Float64, Optional: true},
+}
+
+// WriteMarketChart writes chart, returned by GetCoinMarketChartByCoinID or GetCoinMarketChartRangeByCoinID, as rows of
+// MarketChartColumns. Prices, market caps and volumes are merged by timestamp, missing values are nil. The series are
+// merged as they are streamed in time order; a series out of order is sorted in a copy first.
+func WriteMarketChart(w Writer, coinID, vsCurrency string, chart *coingecko.CoinMarketChartDataResponse) error {
+	series := [3][]coingecko.ChartItem{sortChartItems(chart.Prices), sortChartItems(chart.MarketCaps),
+		sortChartItems(chart.TotalVolumes)}
+	var next [3]int
+	for {
+		// the row time is the earliest time at the heads of the series.
+		var t int64
+		found := false
+		for i, items := range series {
+			if next[i] == len(items) {
+				continue
+			}
+			if head := items[next[i]].Time().UnixMilli(); !found || head < t {
+				t, found = head, true
+			}
+		}
+		if !found {
+			return nil
+		}
+
+		row := []any{time.UnixMilli(t).UTC(), coinID, vsCurrency, nil, nil, nil}
+		for i, items := range series {
+			// the last value wins if a series has several values at t.
+			for next[i] < len(items) && items[next[i]].Time().UnixMilli() == t {
+				row[3+i] = finite(items[next[i]].Value())
+				next[i]++
+			}
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+}
+
+// sortChartItems returns items in time order, items are only copied if they are out of order.
+func sortChartItems(items []coingecko.ChartItem) []coingecko.ChartItem {
+	if sort.SliceIsSorted(items, func(i, j int) bool { return items[i][0] < items[j][0] }) {
+		return items
+	}
+	sorted := append([]coingecko.ChartItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+	return sorted
+}
+
+// OHLCColumns are the columns of WriteOHLC.
+var OHLCColumns = []Column{
+	{Name: "timestamp", Type: Timestamp},
+	{Name: "coin_id", Type: String},
+	{Name: "vs_currency", Type: String},
+	{Name: "open", Type: Float64, Optional: true},
+	{Name: "high", Type: Float64, Optional: true},
+	{Name: "low", Type: Float64, Optional: true},
+	{Name: "close", Type: Float64, Optional: true},
+}
+
+// WriteOHLC writes candles, returned by GetCoinOHLCByCoinID, as rows of OHLCColumns. NaN and infinite prices are nil.
+func WriteOHLC(w Writer, coinID, vsCurrency string, candles []coingecko.CoinOHLCResponse) error {
+	for _, c := range candles {
+		row := []any{c.Time(), coinID, vsCurrency, finite(c.Open()), finite(c.High()), finite(c.Low()), finite(c.Close())}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OHLCVColumns are the columns of WriteOHLCV.
+var OHLCVColumns = []Column{
+	{Name: "timestamp", Type: Timestamp},
+	{Name: "network", Type: String},
+	{Name: "pool_address", Type: String},
+	{Name: "timeframe", Type: String},
+	{Name: "aggregate", Type: Int64},
+	{Name: "currency", Type: String},
+	{Name: "open", Type: Float64, Optional: true},
+	{Name: "high", Type: Float64, Optional: true},
+	{Name: "low", Type: Float64, Optional: true},
+	{Name: "close", Type: Float64, Optional: true},
+	{Name: "volume", Type: Float64, Optional: true},
+}
+
+// OHLCVSource describes the pool and parameters of GetOHLCV candles.
+type OHLCVSource struct {
+	Network     string
+	PoolAddress string
+	// Timeframe is day, hour or minute.
+	Timeframe string
+	// Aggregate is the number of timeframes of each candle, 1 if 0.
+	Aggregate uint
+	// Currency is usd or token, usd if empty.
+	Currency string
+}
+
+// WriteOHLCV writes candles of source, returned by GetOHLCV or OHLCVBackfill, as rows of OHLCVColumns. NaN and infinite
+// values are nil.
+func WriteOHLCV(w Writer, source OHLCVSource, candles []geckoterminal.OHLCVItem) error {
+	aggregate := int64(max(source.Aggregate, 1))
+	currency := source.Currency
+	if currency == "" {
+		currency = "usd"
+	}
+	for _, c := range candles {
+		row := []any{c.Time(), source.Network, source.PoolAddress, source.Timeframe, aggregate, currency,
+			finite(c.Open()), finite(c.High()), finite(c.Low()), finite(c.Close()), finite(c.Volume())}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarketsColumns are the columns of WriteMarkets.
+var MarketsColumns = []Column{
+	{Name: "timestamp", Type: Timestamp},
+	{Name: "coin_id", Type: String},
+	{Name: "symbol", Type: String},
+	{Name: "name", Type: String},
+	{Name: "vs_currency", Type: String},
+	{Name: "price", Type: Float64, Optional: true},
+	{Name: "market_cap", Type: Float64, Optional: true},
+	{Name: "market_cap_rank", Type: Int64, Optional: true},
+	{Name: "fully_diluted_valuation", Type: Float64, Optional: true},
+	{Name: "total_volume", Type: Float64, Optional: true},
+	{Name: "high_24h", Type: Float64, Optional: true},
+	{Name: "low_24h", Type: Float64, Optional: true},
+	{Name: "price_change_24h", Type: Float64, Optional: true},
+	{Name: "price_change_percentage_24h", Type: Float64, Optional: true},
+	{Name: "circulating_supply", Type: Float64, Optional: true},
+	{Name: "total_supply", Type: Float64, Optional: true},
+	{Name: "max_supply", Type: Float64, Optional: true},
+	{Name: "ath", Type: Float64, Optional: true},
+	{Name: "ath_date", Type: Timestamp, Optional: true},
+	{Name: "atl", Type: Float64, Optional: true},
+	{Name: "atl_date", Type: Timestamp, Optional: true},
+	{Name: "last_updated", Type: Timestamp, Optional: true},
+}
+
+// WriteMarkets writes markets, returned by ListCoinsMarketsData at snapshot, as rows of MarketsColumns. The timestamp
+// column is the snapshot time, coins without market cap rank have nil ranks, unknown market caps, fully diluted
+// valuations and volumes are nil and invalid dates are nil.
+func WriteMarkets(w Writer, snapshot time.Time, vsCurrency string,
+	markets []coingecko.ListCoinsMarketsDataResponse) error {
+	for _, m := range markets {
+		var rank, maxSupply any
+		if m.MarketCapRank > 0 {
+			rank = int64(m.MarketCapRank)
+		}
+		if m.MaxSupply != nil {
+			maxSupply = finite(*m.MaxSupply)
+		}
+		row := []any{snapshot, m.ID, m.Symbol, m.Name, vsCurrency,
+			finite(m.CurrentPrice), known(m.MarketCap), rank, known(m.FullyDilutedValuation), known(m.TotalVolume),
+			finite(m.High24h), finite(m.Low24h), finite(m.PriceChange24h), finite(m.PriceChangePercentage24h),
+			finite(m.CirculatingSupply), finite(m.TotalSupply), maxSupply,
+			finite(m.Ath), parseTime(m.AthDate), finite(m.Atl), parseTime(m.AtlDate), parseTime(m.LastUpdated)}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// known returns nil for 0 and for NaN or infinite values. The API sends null for unknown market caps, fully diluted
+// valuations and volumes, which ListCoinsMarketsDataResponse decodes into 0 because its fields are float64.
+func known(v float64) any {
+	if v == 0 {
+		return nil
+	}
+	return finite(v)
+}
+
+// parseTime parses an RFC 3339 time of the API, it returns nil if s is empty or invalid.
+func parseTime(s string) any {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+// rowsWriter records the rows written.
+type rowsWriter struct {
+	columns []Column
+	rows    [][]any
+}
+
+func (r *rowsWriter) Write(row []any) error {
+	if err := checkRow(r.columns, row); err != nil {
+		return err
+	}
+	r.rows = append(r.rows, row)
+	return nil
+}
+
+func (r *rowsWriter) Close() error {
+	return nil
+}
+
+func TestWriteMarketChart(t *testing.T) {
+	w := &rowsWriter{columns: MarketChartColumns}
+	chart := &coingecko.CoinMarketChartDataResponse{
+		Prices:       []coingecko.ChartItem{{1700003600000, 2}, {1700000000000, 1}},
+		MarketCaps:   []coingecko.ChartItem{{1700000000000, 10}},
+		TotalVolumes: []coingecko.ChartItem{{1700000000000, 5}, {1700007200000, 7}},
+	}
+	if err := WriteMarketChart(w, "bitcoin", "usd", chart); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]any{
+		{time.UnixMilli(1700000000000).UTC(), "bitcoin", "usd", 1.0, 10.0, 5.0},
+		{time.UnixMilli(1700003600000).UTC(), "bitcoin", "usd", 2.0, nil, nil},
+		{time.UnixMilli(1700007200000).UTC(), "bitcoin", "usd", nil, nil, 7.0},
+	}
+	if !reflect.DeepEqual(w.rows, want) {
+		t.Fatalf("incorrect rows, wanted: %v, got: %v", want, w.rows)
+	}
+	if chart.Prices[0][0] != 1700003600000 {
+		t.Fatal("chart should not be modified")
+	}
+}
+
+func TestWriteMarketChart_Sorted(t *testing.T) {
+	w := &rowsWriter{columns: MarketChartColumns}
+	prices := []coingecko.ChartItem{{1700000000000, 1}, {1700003600000, math.NaN()}, {1700003600000, 2}}
+	chart := &coingecko.CoinMarketChartDataResponse{Prices: prices, MarketCaps: []coingecko.ChartItem{{1700003600000, 20}}}
+	if err := WriteMarketChart(w, "bitcoin", "usd", chart); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]any{
+		{time.UnixMilli(1700000000000).UTC(), "bitcoin", "usd", 1.0, nil, nil},
+		{time.UnixMilli(1700003600000).UTC(), "bitcoin", "usd", 2.0, 20.0, nil},
+	}
+	if !reflect.DeepEqual(w.rows, want) {
+		t.Fatalf("incorrect rows, wanted: %v, got: %v", want, w.rows)
+	}
+}
+
+func TestWriteOHLC(t *testing.T) {
+	w := &rowsWriter{columns: OHLCColumns}
+	candles := []coingecko.CoinOHLCResponse{{1700000000000, 1, 3, 0.5, 2}, {1700001800000, 2, math.Inf(1), 1, 2}}
+	if err := WriteOHLC(w, "bitcoin", "usd", candles); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]any{
+		{time.UnixMilli(1700000000000).UTC(), "bitcoin", "usd", 1.0, 3.0, 0.5, 2.0},
+		{time.UnixMilli(1700001800000).UTC(), "bitcoin", "usd", 2.0, nil, 1.0, 2.0},
+	}
+	if !reflect.DeepEqual(w.rows, want) {
+		t.Fatalf("incorrect rows, wanted: %v, got: %v", want, w.rows)
+	}
+}
+
+func TestWriteOHLCV(t *testing.T) {
+	w := &rowsWriter{columns: OHLCVColumns}
+	source := OHLCVSource{Network: "eth", PoolAddress: "0xpool", Timeframe: "hour"}
+	candles := []geckoterminal.OHLCVItem{{1700000000, 1, 3, 0.5, 2, 100}, {1700003600, 2, 2, 2, 2, math.NaN()}}
+	if err := WriteOHLCV(w, source, candles); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]any{
+		{time.Unix(1700000000, 0).UTC(), "eth", "0xpool", "hour", int64(1), "usd", 1.0, 3.0, 0.5, 2.0, 100.0},
+		{time.Unix(1700003600, 0).UTC(), "eth", "0xpool", "hour", int64(1), "usd", 2.0, 2.0, 2.0, 2.0, nil},
+	}
+	if !reflect.DeepEqual(w.rows, want) {
+		t.Fatalf("incorrect rows, wanted: %v, got: %v", want, w.rows)
+	}
+}
+
+func TestWriteMarkets(t *testing.T) {
+	w := &rowsWriter{columns: MarketsColumns}
+	maxSupply := 21e6
+	markets := []coingecko.ListCoinsMarketsDataResponse{
+		{CurrentPrice: 42000, MarketCap: 8e11, MarketCapRank: 1, TotalVolume: 2e10, MaxSupply: &maxSupply,
+			AthDate: "2021-11-10T14:24:11.849Z"},
+		{CurrentPrice: 0.1, AthDate: "invalid"},
+	}
+	markets[0].ID = "bitcoin"
+	if err := WriteMarkets(w, testTime, "usd", markets); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.rows) != 2 {
+		t.Fatalf("incorrect rows, got: %v", w.rows)
+	}
+	first, second := w.rows[0], w.rows[1]
+	athDate := time.Date(2021, 11, 10, 14, 24, 11, 849e6, time.UTC)
+	if first[0] != testTime || first[1] != "bitcoin" || first[6] != 8e11 || first[7] != int64(1) || first[9] != 2e10 ||
+		first[16] != 21e6 || first[18] != athDate {
+		t.Fatalf("incorrect first row, got: %v", first)
+	}
+	if second[5] != 0.1 || second[6] != nil || second[7] != nil || second[8] != nil || second[9] != nil ||
+		second[16] != nil || second[18] != nil {
+		t.Fatalf("incorrect second row, got: %v", second)
+	}
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"encoding/binary"
+)
+
+// Types of the Thrift compact protocol.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the Thrift structs of Parquet page headers and file metadata with the compact protocol.
+type thriftWriter struct {
+	buf []byte
+	// last is the id of the last field of the current struct, stack holds those of the outer structs.
+	last  int16
+	stack []int16
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.buf = binary.AppendVarint(t.buf, int64(id))
+	}
+	t.last = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.buf = binary.AppendVarint(t.buf, v)
+}
+
+func (t *thriftWriter) bool(id int16, v bool) {
+	if v {
+		t.fieldHeader(id, thriftTrue)
+	} else {
+		t.fieldHeader(id, thriftFalse)
+	}
+}
+
+func (t *thriftWriter) string(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.stringValue(s)
+}
+
+// stringValue appends s without field header, as list element.
+func (t *thriftWriter) stringValue(s string) {
+	t.buf = binary.AppendUvarint(t.buf, uint64(len(s)))
+	t.buf = append(t.buf, s...)
+}
+
+// i32Value appends v without field header, as list element.
+func (t *thriftWriter) i32Value(v int32) {
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+// list appends the header of a list field of size elements of typ, the elements are appended next.
+func (t *thriftWriter) list(id int16, typ byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf = append(t.buf, byte(size)<<4|typ)
+		return
+	}
+	t.buf = append(t.buf, 0xf0|typ)
+	t.buf = binary.AppendUvarint(t.buf, uint64(size))
+}
+
+// structField begins a struct field, which is ended by structEnd.
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.structBegin()
+}
+
+// structBegin begins a struct without field header, as list element or top-level struct.
+func (t *thriftWriter) structBegin() {
+	t.stack = append(t.stack, t.last)
+	t.last = 0
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf = append(t.buf, 0)
+	t.last = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
@@ -0,0 +1,161 @@
+package geckoterminal
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// maxOHLCVLimit is the maximum number of candles returned by GetOHLCV API.
+const maxOHLCVLimit = 1000
+
+// timeframeSeconds maps timeframe param of GetOHLCV API into its length in seconds.
+var timeframeSeconds = map[string]int64{
+	"minute": 60,
+	"hour":   60 * 60,
+	"day":    24 * 60 * 60,
+}
+
+// OHLCVBackfillOptions configures OHLCVBackfill.
+type OHLCVBackfillOptions struct {
+	// Aggregate is the aggregate param of GetOHLCV API, default: 1.
+	Aggregate uint
+	// Currency is the currency param of GetOHLCV API, default: usd.
+	Currency string
+	// Token is the token param of GetOHLCV API, default: base.
+	Token string
+	// Before is the time to walk backward from, default: now.
+	Before time.Time
+	// Start is the time to stop at, candles ending before it are dropped. Default: pool creation time(PoolCreatedAt);
+	// if it is unknown, backfill stops when GetOHLCV API returns no more data.
+	Start time.Time
+}
+
+// OHLCVBackfill walks OHLCV data of a pool backward by moving before_timestamp of GetOHLCV API.
+type OHLCVBackfill struct {
+	client      *Client
+	network     string
+	poolAddress string
+	timeframe   string
+	opts        OHLCVBackfillOptions
+
+	period       int64
+	cursor       int64
+	start        int64
+	startChecked bool
+	// paged reports whether a page was fetched, candles at or after cursor were returned by previous pages then.
+	paged bool
+	done  bool
+}
+
+// NewOHLCVBackfill returns an OHLCVBackfill of the pool. timeframe is the timeframe param of GetOHLCV API, i.e. day,
+// hour or minute.
+func (c *Client) NewOHLCVBackfill(network, poolAddress, timeframe string, opts OHLCVBackfillOptions) *OHLCVBackfill {
+	before := opts.Before
+	if before.IsZero() {
+		before = time.Now()
+	}
+	b := &OHLCVBackfill{
+		client:      c,
+		network:     network,
+		poolAddress: poolAddress,
+		timeframe:   timeframe,
+		opts:        opts,
+		period:      timeframeSeconds[timeframe] * int64(max(opts.Aggregate, 1)),
+		cursor:      before.Unix(),
+	}
+	if !opts.Start.IsZero() {
+		b.start = opts.Start.Unix()
+		b.startChecked = true
+	}
+	return b
+}
+
+// Next returns the next older page of candles in chronological order, candles returned by previous pages are
+// removed. It returns io.EOF when there are no more candles.
+func (b *OHLCVBackfill) Next(ctx context.Context) ([]OHLCVItem, error) {
+	if b.done {
+		return nil, io.EOF
+	}
+	if !b.startChecked {
+		if err := b.checkPoolCreatedAt(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := b.client.GetOHLCV(ctx, b.network, b.poolAddress, b.timeframe, b.opts.Aggregate, b.cursor,
+		maxOHLCVLimit, b.opts.Currency, b.opts.Token)
+	if err != nil {
+		slog.Error("failed to get OHLCV page", "before_timestamp", b.cursor, "error", err)
+		return nil, err
+	}
+
+	list := resp.Data.Attributes.OHLCVList
+	oldest := b.cursor
+	items := make([]OHLCVItem, 0, len(list))
+	for _, item := range list {
+		ts := int64(item[0])
+		if ts < oldest {
+			oldest = ts
+		}
+		if (b.paged && ts >= b.cursor) || ts+b.period <= b.start {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	// stop when the api returns an empty list, only known candles, or candles reach the start time.
+	if len(items) == 0 || oldest >= b.cursor || oldest <= b.start {
+		b.done = true
+	}
+	b.cursor = oldest
+	b.paged = true
+	if len(items) == 0 {
+		return nil, io.EOF
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i][0] < items[j][0]
+	})
+	return items, nil
+}
+
+// All walks all pages and returns all candles in chronological order.
+func (b *OHLCVBackfill) All(ctx context.Context) ([]OHLCVItem, error) {
+	var pages [][]OHLCVItem
+	var total int
+	for {
+		items, err := b.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, items)
+		total += len(items)
+	}
+
+	// pages are fetched from newest to oldest.
+	result := make([]OHLCVItem, 0, total)
+	for i := len(pages) - 1; i >= 0; i-- {
+		result = append(result, pages[i]...)
+	}
+	return result, nil
+}
+
+// checkPoolCreatedAt uses pool creation time as start time.
+func (b *OHLCVBackfill) checkPoolCreatedAt(ctx context.Context) error {
+	pool, err := b.client.GetSpecificPool(ctx, b.network, b.poolAddress, nil)
+	if err != nil {
+		slog.Error("failed to get pool created at", "error", err)
+		return err
+	}
+	if createdAt := pool.Data.Attributes.PoolCreatedAt; createdAt != nil {
+		b.start = createdAt.Unix()
+	}
+	b.startChecked = true
+	return nil
+}
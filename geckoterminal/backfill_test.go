@@ -0,0 +1,105 @@
+package geckoterminal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockOHLCVServer mocks a pool created at createdAt with hourly candles until end. Candles at or before
+// before_timestamp are returned from newest to oldest.
+func mockOHLCVServer(t *testing.T, createdAt, end int64) (*httptest.Server, *int) {
+	t.Helper()
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/ohlcv/") {
+			created := time.Unix(createdAt, 0).UTC().Format(time.RFC3339)
+			_, _ = fmt.Fprintf(w, `{"data":{"id":"eth_0x1","type":"pool","attributes":{"pool_created_at":"%s"}}}`, created)
+			return
+		}
+
+		calls++
+		before, _ := strconv.ParseInt(r.URL.Query().Get("before_timestamp"), 10, 64)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var data OHLCVResponse
+		for ts := end - end%3600; ts >= createdAt-createdAt%3600 && len(data.Data.Attributes.OHLCVList) < limit; ts -= 3600 {
+			if ts <= before {
+				data.Data.Attributes.OHLCVList = append(data.Data.Attributes.OHLCVList, OHLCVItem{float64(ts), 1, 1, 1, 1, 1})
+			}
+		}
+		resp, _ := json.Marshal(data)
+		_, _ = w.Write(resp)
+	}))
+	return server, &calls
+}
+
+func TestOHLCVBackfill_All(t *testing.T) {
+	end := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC).Unix()
+	createdAt := end - 2500*3600 - 1800
+	server, calls := mockOHLCVServer(t, createdAt, end)
+	defer server.Close()
+
+	client := setup(t, server)
+	backfill := client.NewOHLCVBackfill("eth", "0x1", "hour", OHLCVBackfillOptions{Before: time.Unix(end, 0)})
+	items, err := backfill.All(context.TODO())
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(items) != 2502 {
+		t.Fatalf("incorrect candle number, wanted: 2502, got: %d", len(items))
+	}
+	for i := 1; i < len(items); i++ {
+		if items[i][0] <= items[i-1][0] {
+			t.Fatalf("candles should be chronological and unique, got %v after %v", items[i], items[i-1])
+		}
+	}
+	if *calls != 3 {
+		t.Fatalf("incorrect call number, wanted: 3, got: %d", *calls)
+	}
+	if _, err = backfill.Next(context.TODO()); err != io.EOF {
+		t.Fatalf("backfill should be done, got: %v", err)
+	}
+}
+
+func TestOHLCVBackfill_Start(t *testing.T) {
+	end := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC).Unix()
+	server, _ := mockOHLCVServer(t, end-5000*3600, end)
+	defer server.Close()
+
+	client := setup(t, server)
+	backfill := client.NewOHLCVBackfill("eth", "0x1", "hour", OHLCVBackfillOptions{
+		Before: time.Unix(end, 0),
+		Start:  time.Unix(end-10*3600, 0),
+	})
+	items, err := backfill.All(context.TODO())
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(items) != 11 || int64(items[0][0]) != end-10*3600 {
+		t.Fatalf("incorrect candles, got: %v", items)
+	}
+}
+
+func TestOHLCVBackfill_EmptyList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":"eth_0x1","type":"pool","attributes":{"ohlcv_list":[]}}}`))
+	}))
+	defer server.Close()
+
+	client := setup(t, server)
+	backfill := client.NewOHLCVBackfill("eth", "0x1", "day", OHLCVBackfillOptions{})
+	items, err := backfill.All(context.TODO())
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("candles should be empty, got: %v", items)
+	}
+}
@@ -3,35 +3,103 @@ package geckoterminal
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/bufdata/coingecko-api/util"
 )
 
 // Client struct
 type Client struct {
+	apiURL     string
 	httpClient *http.Client
+	limiter    *util.RateLimiter
+	// middlewares wrap send, the first one is the outermost.
+	middlewares []util.Middleware
+	tracer      util.Tracer
+}
+
+// Option configures optional settings of Client.
+type Option func(*Client)
+
+// WithRateLimit limits the client to callsPerMinute requests per minute; requests beyond the limit wait until they
+// are allowed. By default, requests are not limited.
+//
+// Note: rate limit of GeckoTerminal API is 30 calls per minute.
+func WithRateLimit(callsPerMinute int) Option {
+	return func(c *Client) {
+		c.limiter = util.NewRateLimiter(callsPerMinute)
+	}
+}
+
+// WithBaseURL sends requests to baseURL instead of GeckoTerminal API, e.g. a caching proxy like cmd/cgproxy.
+// baseURL includes the API version path, e.g. http://localhost:8080/api/v2.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.apiURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithMiddleware wraps every request of the client with middlewares, e.g. metrics.ClientMetrics.Middleware or
+// util.Retry. The first middleware is the outermost one, options may be repeated to append more middlewares.
+func WithMiddleware(middlewares ...util.Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithTracer traces every API call of the client with tracer, e.g. an OpenTelemetry tracer adapted by the cgotel
+// module.
+func WithTracer(tracer util.Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
 }
 
 // NewGeckoTerminal create a new GeckoTerminal API client.
-func NewGeckoTerminal(httpClient *http.Client) *Client {
+func NewGeckoTerminal(httpClient *http.Client, opts ...Option) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
 	util.GetLogger("GeckoTerminal")
-	return &Client{
+	c := &Client{
+		apiURL:     geckoTerminalAPIEndpoint,
 		httpClient: httpClient,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// sendReq sends a request of the client method to endpoint, pathTemplate is the path constant of endpoint.
+func (c *Client) sendReq(ctx context.Context, method, pathTemplate, endpoint string) ([]byte, http.Header, error) {
+	call := util.CallInfo{API: apiName, Method: method, PathTemplate: pathTemplate, Endpoint: endpoint}
+	send := util.Chain(c.send, c.middlewares...)
+	if c.tracer == nil {
+		return send(ctx, call)
+	}
+	return util.TraceCall(ctx, c.tracer, call, send)
 }
 
-func (c *Client) sendReq(ctx context.Context, endpoint string) ([]byte, http.Header, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+func (c *Client) send(ctx context.Context, call util.CallInfo) ([]byte, http.Header, error) {
+	if c.limiter != nil {
+		wait, err := c.limiter.Wait(ctx)
+		if trace := util.ContextRequestTrace(ctx); trace != nil && trace.RateLimitWait != nil {
+			trace.RateLimitWait(wait)
+		}
+		if err != nil {
+			slog.Error("failed to wait for rate limiter", "error", err)
+			return nil, nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, call.Endpoint, nil)
 	if err != nil {
-		slog.Error("failed to new request with context", "endpoint", endpoint, "error", err)
+		slog.Error("failed to new request with context", "endpoint", call.Endpoint, "error", err)
 		return nil, nil, err
 	}
 
@@ -58,8 +126,7 @@ func (c *Client) doAPI(req *http.Request) ([]byte, http.Header, error) {
 			slog.Error("failed to read error response", "error", err)
 			return nil, nil, err
 		}
-		return nil, nil, fmt.Errorf("failed to call %s, status code: %d, error message: %s", req.URL.String(),
-			resp.StatusCode, string(data))
+		return nil, nil, &util.APIError{URL: req.URL.String(), StatusCode: resp.StatusCode, Message: string(data)}
 	}
 
 	buf := &bytes.Buffer{}
@@ -0,0 +1,25 @@
+package geckoterminal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithBaseURL(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewGeckoTerminal(nil, WithBaseURL(server.URL+"/api/v2/"))
+	if _, err := client.GetNetworks(context.TODO(), 1); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if path != "/api/v2/networks" {
+		t.Fatalf("incorrect path, wanted: /api/v2/networks, got: %s", path)
+	}
+}
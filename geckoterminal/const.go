@@ -5,6 +5,9 @@ const (
 	geckoTerminalAPIEndpoint = "https://api.geckoterminal.com/api/v2"
 )
 
+// apiName is the name of GeckoTerminal API in traces.
+const apiName = "geckoterminal"
+
 const (
 	acceptHeader = "accept"
 	jsonHeader   = "application/json"
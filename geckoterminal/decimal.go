@@ -0,0 +1,271 @@
+package geckoterminal
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// defaultDecimalPlaces is used by Decimal.String when the value has no finite decimal representation.
+const defaultDecimalPlaces = 18
+
+// Decimal is an arbitrary-precision decimal number. GeckoTerminal returns prices, reserves and volumes as strings,
+// Decimal keeps them exact instead of rounding them into float64.
+type Decimal struct {
+	rat big.Rat
+}
+
+// ParseDecimal parses a decimal string such as "0.000000012345" or "1.5e-7" into Decimal. Fractions such as "1/3"
+// are rejected.
+func ParseDecimal(s string) (*Decimal, error) {
+	var d Decimal
+	if strings.Contains(s, "/") {
+		return nil, fmt.Errorf("invalid decimal value: %q", s)
+	}
+	if _, ok := d.rat.SetString(strings.TrimSpace(s)); !ok {
+		return nil, fmt.Errorf("invalid decimal value: %q", s)
+	}
+	return &d, nil
+}
+
+// Rat returns a copy of d as big.Rat.
+func (d *Decimal) Rat() *big.Rat {
+	return new(big.Rat).Set(&d.rat)
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d *Decimal) Float64() float64 {
+	f, _ := d.rat.Float64()
+	return f
+}
+
+// Cmp compares d and other, returns -1 if d < other, 0 if d == other and +1 if d > other.
+func (d *Decimal) Cmp(other *Decimal) int {
+	return d.rat.Cmp(&other.rat)
+}
+
+// Sign returns -1 if d < 0, 0 if d == 0 and +1 if d > 0.
+func (d *Decimal) Sign() int {
+	return d.rat.Sign()
+}
+
+// Mul returns the product of d and other.
+func (d *Decimal) Mul(other *Decimal) *Decimal {
+	var result Decimal
+	result.rat.Mul(&d.rat, &other.rat)
+	return &result
+}
+
+// Quo returns the quotient of d and other, other must not be zero.
+func (d *Decimal) Quo(other *Decimal) *Decimal {
+	var result Decimal
+	result.rat.Quo(&d.rat, &other.rat)
+	return &result
+}
+
+// String returns the exact decimal representation of d without trailing zeros. Values without finite decimal
+// representation are rounded to 18 decimal places.
+func (d *Decimal) String() string {
+	s := d.rat.FloatString(decimalPlaces(d.rat.Denom()))
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// decimalPlaces returns the number of decimal places needed to represent a fraction with denominator denom exactly.
+func decimalPlaces(denom *big.Int) int {
+	n := new(big.Int).Set(denom)
+	two, five := big.NewInt(2), big.NewInt(5)
+	mod := new(big.Int)
+	var twos, fives int
+	for n.Cmp(big.NewInt(1)) != 0 {
+		switch {
+		case mod.Mod(n, two).Sign() == 0:
+			n.Quo(n, two)
+			twos++
+		case mod.Mod(n, five).Sign() == 0:
+			n.Quo(n, five)
+			fives++
+		default:
+			return defaultDecimalPlaces
+		}
+	}
+	return max(twos, fives)
+}
+
+// parseOptionalDecimal parses s into Decimal; it returns nil if s is nil or empty.
+func parseOptionalDecimal(s *string) (*Decimal, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	return ParseDecimal(*s)
+}
+
+// BaseTokenPriceUSDDecimal returns BaseTokenPriceUSD as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) BaseTokenPriceUSDDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.BaseTokenPriceUSD)
+}
+
+// BaseTokenPriceNativeCurrencyDecimal returns BaseTokenPriceNativeCurrency as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) BaseTokenPriceNativeCurrencyDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.BaseTokenPriceNativeCurrency)
+}
+
+// QuoteTokenPriceUSDDecimal returns QuoteTokenPriceUSD as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) QuoteTokenPriceUSDDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.QuoteTokenPriceUSD)
+}
+
+// QuoteTokenPriceNativeCurrencyDecimal returns QuoteTokenPriceNativeCurrency as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) QuoteTokenPriceNativeCurrencyDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.QuoteTokenPriceNativeCurrency)
+}
+
+// BaseTokenPriceQuoteTokenDecimal returns BaseTokenPriceQuoteToken as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) BaseTokenPriceQuoteTokenDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.BaseTokenPriceQuoteToken)
+}
+
+// QuoteTokenPriceBaseTokenDecimal returns QuoteTokenPriceBaseToken as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) QuoteTokenPriceBaseTokenDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.QuoteTokenPriceBaseToken)
+}
+
+// TokenPriceUSDDecimal returns TokenPriceUSD as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) TokenPriceUSDDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.TokenPriceUSD)
+}
+
+// FDVUSDDecimal returns FDVUSD as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) FDVUSDDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.FDVUSD)
+}
+
+// MarketCapUSDDecimal returns MarketCapUSD as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) MarketCapUSDDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.MarketCapUSD)
+}
+
+// ReserveInUSDDecimal returns ReserveInUSD as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) ReserveInUSDDecimal() (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(p.ReserveInUSD)
+}
+
+// PriceChangePercentageDecimal returns price change percentage of the given duration (m5, h1, h6, h24) as Decimal,
+// nil if it is absent.
+func (p *PoolAttributesItem) PriceChangePercentageDecimal(duration string) (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	v, ok := p.PriceChangePercentage[duration]
+	if !ok {
+		return nil, nil
+	}
+	return parseOptionalDecimal(&v)
+}
+
+// VolumeUSDDecimal returns volume in USD of the given duration (m5, h1, h6, h24) as Decimal, nil if it is absent.
+func (p *PoolAttributesItem) VolumeUSDDecimal(duration string) (*Decimal, error) {
+	if p == nil {
+		return nil, nil
+	}
+	v, ok := p.VolumeUSD[duration]
+	if !ok {
+		return nil, nil
+	}
+	return parseOptionalDecimal(&v)
+}
+
+// PriceUSDDecimal returns PriceUSD as Decimal, nil if it is absent.
+func (t *TokenAttributesItem) PriceUSDDecimal() (*Decimal, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(&t.PriceUSD)
+}
+
+// FDVUSDDecimal returns FDVUSD as Decimal, nil if it is absent.
+func (t *TokenAttributesItem) FDVUSDDecimal() (*Decimal, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(&t.FDVUSD)
+}
+
+// TotalReserveInUSDDecimal returns TotalReserveInUSD as Decimal, nil if it is absent.
+func (t *TokenAttributesItem) TotalReserveInUSDDecimal() (*Decimal, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(&t.TotalReserveInUSD)
+}
+
+// MarketCapUSDDecimal returns MarketCapUSD as Decimal, nil if it is absent.
+func (t *TokenAttributesItem) MarketCapUSDDecimal() (*Decimal, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(t.MarketCapUSD)
+}
+
+// VolumeUSDDecimal returns volume in USD of the given duration (h24) as Decimal, nil if it is absent.
+func (t *TokenAttributesItem) VolumeUSDDecimal(duration string) (*Decimal, error) {
+	if t == nil {
+		return nil, nil
+	}
+	v, ok := t.VolumeUSD[duration]
+	if !ok {
+		return nil, nil
+	}
+	return parseOptionalDecimal(&v)
+}
+
+// TotalSupplyDecimal returns TotalSupply in human units, i.e. the raw total supply divided by 10^Decimals,
+// nil if it is absent.
+func (t *TokenAttributesItem) TotalSupplyDecimal() (*Decimal, error) {
+	if t == nil {
+		return nil, nil
+	}
+	raw, err := parseOptionalDecimal(&t.TotalSupply)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	if t.Decimals < 0 {
+		return nil, fmt.Errorf("invalid token decimals: %d", t.Decimals)
+	}
+
+	var unit Decimal
+	unit.rat.SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals)), nil))
+	return raw.Quo(&unit), nil
+}
@@ -0,0 +1,108 @@
+package geckoterminal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	cases := []struct {
+		name         string
+		value        string
+		wantedIsErr  bool
+		wantedResult string
+		wantedErrStr string
+	}{
+		{
+			name:         "small price",
+			value:        "0.000000012345678901234567",
+			wantedIsErr:  false,
+			wantedResult: "0.000000012345678901234567",
+			wantedErrStr: "",
+		},
+		{
+			name:         "trailing zeros",
+			value:        "1234.5000",
+			wantedIsErr:  false,
+			wantedResult: "1234.5",
+			wantedErrStr: "",
+		},
+		{
+			name:         "exponent",
+			value:        "1.5e-7",
+			wantedIsErr:  false,
+			wantedResult: "0.00000015",
+			wantedErrStr: "",
+		},
+		{
+			name:         "invalid value",
+			value:        "abc",
+			wantedIsErr:  true,
+			wantedResult: "",
+			wantedErrStr: "invalid decimal value",
+		},
+		{
+			name:         "fraction",
+			value:        "1/3",
+			wantedIsErr:  true,
+			wantedResult: "",
+			wantedErrStr: "invalid decimal value",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDecimal(tt.value)
+			if tt.wantedIsErr {
+				if err == nil || !strings.Contains(err.Error(), tt.wantedErrStr) {
+					t.Fatalf("incorrect error, wanted error: %v, got error: %v", tt.wantedErrStr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if result.String() != tt.wantedResult {
+				t.Fatalf("incorrect decimal, wanted result: %s, got result: %s", tt.wantedResult, result.String())
+			}
+		})
+	}
+}
+
+func TestPoolAttributesItem_Decimal(t *testing.T) {
+	var nilPool *PoolAttributesItem
+	if d, err := nilPool.BaseTokenPriceUSDDecimal(); d != nil || err != nil {
+		t.Fatalf("nil pool should return nil decimal, got: %v, %v", d, err)
+	}
+
+	price := "0.000000000001"
+	pool := &PoolAttributesItem{BaseTokenPriceUSD: &price, VolumeUSD: map[string]string{"h24": "1000.25"}}
+	d, err := pool.BaseTokenPriceUSDDecimal()
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if d.String() != price {
+		t.Fatalf("incorrect price, wanted result: %s, got result: %s", price, d.String())
+	}
+	if d, err = pool.ReserveInUSDDecimal(); d != nil || err != nil {
+		t.Fatalf("absent reserve should return nil decimal, got: %v, %v", d, err)
+	}
+	if d, err = pool.VolumeUSDDecimal("h24"); err != nil || d.String() != "1000.25" {
+		t.Fatalf("incorrect volume, got: %v, %v", d, err)
+	}
+}
+
+func TestTokenAttributesItem_TotalSupplyDecimal(t *testing.T) {
+	token := &TokenAttributesItem{TotalSupply: "1000000000000000000000001.0", Decimals: 18}
+	d, err := token.TotalSupplyDecimal()
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if d.String() != "1000000.000000000000000001" {
+		t.Fatalf("incorrect total supply, got result: %s", d.String())
+	}
+
+	token = &TokenAttributesItem{}
+	if d, err = token.TotalSupplyDecimal(); d != nil || err != nil {
+		t.Fatalf("empty total supply should return nil decimal, got: %v, %v", d, err)
+	}
+}
@@ -0,0 +1,12 @@
+package geckoterminal
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// setup returns a client whose requests are sent to the mock server.
+func setup(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	return NewGeckoTerminal(nil, WithBaseURL(server.URL+"/api/v2"))
+}
@@ -24,8 +24,8 @@ func (c *Client) GetNetworks(ctx context.Context, page uint) (*NetworksResponse,
 	}
 	params.Add("page", strconv.Itoa(int(page)))
 
-	endpoint := fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, getNetworksPath, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, getNetworksPath, params.Encode())
+	resp, _, err := c.sendReq(ctx, "GetNetworks", getNetworksPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to networks api", "error", err)
 		return nil, err
@@ -62,8 +62,8 @@ func (c *Client) GetDexes(ctx context.Context, network string, page uint) (*Dexe
 	params.Add("page", strconv.Itoa(int(page)))
 
 	path := fmt.Sprintf(getDexesPath, network)
-	endpoint := fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
+	resp, _, err := c.sendReq(ctx, "GetDexes", getDexesPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get dexes api", "error", err)
 		return nil, err
@@ -108,12 +108,12 @@ func (c *Client) GetSpecificPool(ctx context.Context, network, address string, i
 	path := fmt.Sprintf(getSpecificPoolPath, network, address)
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetSpecificPool", getSpecificPoolPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get specific pool api", "error", err)
 		return nil, err
@@ -161,12 +161,12 @@ func (c *Client) GetMultiPools(ctx context.Context, network string, include, add
 	path := fmt.Sprintf(getMultiPoolsPath, network, address)
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetMultiPools", getMultiPoolsPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get multi pools api", "error", err)
 		return nil, err
@@ -206,12 +206,12 @@ func (c *Client) GetTop20PoolsOnOneNetwork(ctx context.Context, network string,
 	path := fmt.Sprintf(getTop20PoolsPath, network)
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetTop20PoolsOnOneNetwork", getTop20PoolsPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get top 20 pools api", "error", err)
 		return nil, err
@@ -253,12 +253,12 @@ func (c *Client) GetTop20PoolsOnOneDex(ctx context.Context, network, dex string,
 	path := fmt.Sprintf(getTop20PoolsOnOneDexPath, network, dex)
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetTop20PoolsOnOneDex", getTop20PoolsOnOneDexPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get top 20 pools on one dex api", "error", err)
 		return nil, err
@@ -298,12 +298,12 @@ func (c *Client) GetLatest20PoolsOnOneNetwork(ctx context.Context, network strin
 	path := fmt.Sprintf(getLatest20PoolsOnOneNetworkPath, network)
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetLatest20PoolsOnOneNetwork", getLatest20PoolsOnOneNetworkPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get latest 20 pools on one network api", "error", err)
 		return nil, err
@@ -334,12 +334,12 @@ func (c *Client) GetLatest20PoolsOnAllNetworks(ctx context.Context, include []st
 
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, getLatest20PoolsOnAllNetworkPath, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, getLatest20PoolsOnAllNetworkPath, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, getLatest20PoolsOnAllNetworkPath)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, getLatest20PoolsOnAllNetworkPath)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetLatest20PoolsOnAllNetworks", getLatest20PoolsOnAllNetworkPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get latest 20 pools on all networks api", "error", err)
 		return nil, err
@@ -381,12 +381,12 @@ func (c *Client) SearchPools(ctx context.Context, query, network string, include
 
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, searchPoolsPath, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, searchPoolsPath, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, searchPoolsPath)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, searchPoolsPath)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "SearchPools", searchPoolsPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to search pools api", "error", err)
 		return nil, err
@@ -432,12 +432,12 @@ func (c *Client) GetTop20PoolsForOneToken(ctx context.Context, network, tokenAdd
 	path := fmt.Sprintf(getTop20PoolsForOneTokenPath, network, tokenAddress)
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetTop20PoolsForOneToken", getTop20PoolsForOneTokenPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get top 20 pools for one token api", "error", err)
 		return nil, err
@@ -482,12 +482,12 @@ func (c *Client) GetSpecificTokenOnOneNetwork(ctx context.Context, network, addr
 	path := fmt.Sprintf(getSpecificTokenOnOneNetworkPath, network, address)
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetSpecificTokenOnOneNetwork", getSpecificTokenOnOneNetworkPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get specific token on one network api", "error", err)
 		return nil, err
@@ -536,12 +536,12 @@ func (c *Client) GetMultiTokensOnOneNetwork(ctx context.Context, network string,
 	path := fmt.Sprintf(getMultiTokensOnOneNetworkPath, network, addressParam)
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, path)
 	}
 
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetMultiTokensOnOneNetwork", getMultiTokensOnOneNetworkPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get multi tokens on one network api", "error", err)
 		return nil, err
@@ -573,8 +573,8 @@ func (c *Client) GetSpecificTokenInfoOnOneNetwork(ctx context.Context, network,
 	}
 
 	path := fmt.Sprintf(getSpecificTokenInfoOnOneNetworkPath, network, address)
-	endpoint := fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
+	resp, _, err := c.sendReq(ctx, "GetSpecificTokenInfoOnOneNetwork", getSpecificTokenInfoOnOneNetworkPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get specific token info on one network api", "error", err)
 		return nil, err
@@ -606,8 +606,8 @@ func (c *Client) GetPoolTokensInfoOnOneNetwork(ctx context.Context, network, poo
 	}
 
 	path := fmt.Sprintf(getPoolTokensInfoOnOneNetworkPath, network, poolAddress)
-	endpoint := fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, path)
-	resp, _, err := c.sendReq(ctx, endpoint)
+	endpoint := fmt.Sprintf("%s%s", c.apiURL, path)
+	resp, _, err := c.sendReq(ctx, "GetPoolTokensInfoOnOneNetwork", getPoolTokensInfoOnOneNetworkPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get pool tokens info on one network api", "error", err)
 		return nil, err
@@ -638,11 +638,11 @@ func (c *Client) GetRecentlyUpdated100TokensInfo(ctx context.Context, include []
 
 	var endpoint string
 	if len(params) != 0 {
-		endpoint = fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, getRecentlyUpdated100TokensInfoPath, params.Encode())
+		endpoint = fmt.Sprintf("%s%s?%s", c.apiURL, getRecentlyUpdated100TokensInfoPath, params.Encode())
 	} else {
-		endpoint = fmt.Sprintf("%s%s", geckoTerminalAPIEndpoint, getRecentlyUpdated100TokensInfoPath)
+		endpoint = fmt.Sprintf("%s%s", c.apiURL, getRecentlyUpdated100TokensInfoPath)
 	}
-	resp, _, err := c.sendReq(ctx, endpoint)
+	resp, _, err := c.sendReq(ctx, "GetRecentlyUpdated100TokensInfo", getRecentlyUpdated100TokensInfoPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get recently updated 100 tokens info api", "error", err)
 		return nil, err
@@ -711,13 +711,13 @@ func (c *Client) GetOHLCV(ctx context.Context, network, poolAddress, timeframe s
 		currency = "usd"
 	}
 	params.Add("currency", currency)
-	if token == "" {
+	if token != "" {
 		params.Add("token", token)
 	}
 
 	path := fmt.Sprintf(getOHLCVPath, network, poolAddress, timeframe)
-	endpoint := fmt.Sprintf("%s%s?%s", geckoTerminalAPIEndpoint, path, params.Encode())
-	resp, _, err := c.sendReq(ctx, endpoint)
+	endpoint := fmt.Sprintf("%s%s?%s", c.apiURL, path, params.Encode())
+	resp, _, err := c.sendReq(ctx, "GetOHLCV", getOHLCVPath, endpoint)
 	if err != nil {
 		slog.Error("failed to send request to get OHLCV api", "error", err)
 		return nil, err
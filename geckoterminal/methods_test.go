@@ -0,0 +1,37 @@
+package geckoterminal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetOHLCV_Token(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{"data":{"id":"x","type":"ohlcv_request_response","attributes":{"ohlcv_list":[]}}}`))
+	}))
+	defer server.Close()
+	client := setup(t, server)
+
+	var cases = []struct {
+		name   string
+		token  string
+		wanted string
+	}{
+		{name: "default token", wanted: "aggregate=1&currency=usd&limit=100"},
+		{name: "quote token", token: "quote", wanted: "aggregate=1&currency=usd&limit=100&token=quote"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.GetOHLCV(context.TODO(), "eth", "0x1", "day", 1, 0, 0, "", tt.token); err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if query != tt.wanted {
+				t.Fatalf("incorrect query, wanted: %s, got: %s", tt.wanted, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package geckoterminal
+
+import (
+	"time"
+)
+
+// OHLCV is a single candle returned by GetOHLCV API.
+type OHLCV struct {
+	Time   time.Time `json:"time"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"`
+}
+
+// Time returns the open time of the candle; GeckoTerminal timestamps are in unix seconds.
+func (o OHLCVItem) Time() time.Time {
+	return time.Unix(int64(o[0]), 0).UTC()
+}
+
+// Open returns the opening price of the candle.
+func (o OHLCVItem) Open() float64 {
+	return o[1]
+}
+
+// High returns the highest price of the candle.
+func (o OHLCVItem) High() float64 {
+	return o[2]
+}
+
+// Low returns the lowest price of the candle.
+func (o OHLCVItem) Low() float64 {
+	return o[3]
+}
+
+// Close returns the closing price of the candle.
+func (o OHLCVItem) Close() float64 {
+	return o[4]
+}
+
+// Volume returns the volume of the candle.
+func (o OHLCVItem) Volume() float64 {
+	return o[5]
+}
+
+// OHLCV converts the response item into OHLCV.
+func (o OHLCVItem) OHLCV() OHLCV {
+	return OHLCV{Time: o.Time(), Open: o.Open(), High: o.High(), Low: o.Low(), Close: o.Close(), Volume: o.Volume()}
+}
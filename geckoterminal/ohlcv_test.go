@@ -0,0 +1,15 @@
+package geckoterminal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOHLCVItem_OHLCV(t *testing.T) {
+	item := OHLCVItem{1700000000, 1, 4, 0.5, 2, 1000}
+	wanted := OHLCV{Time: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), Open: 1, High: 4, Low: 0.5, Close: 2, Volume: 1000}
+	if result := item.OHLCV(); !reflect.DeepEqual(result, wanted) {
+		t.Fatalf("incorrect ohlcv, wanted result: %+v, got result: %+v", wanted, result)
+	}
+}
@@ -0,0 +1,223 @@
+// Package liquidity analyzes the ±2% order book depth of coin tickers across exchanges.
+package liquidity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+const (
+	defaultMaxPages = 10
+
+	// depthPercentage is the price move covered by cost_to_move_up_usd and cost_to_move_down_usd.
+	depthPercentage = 2.0
+)
+
+// Options configures Fetch.
+type Options struct {
+	// ExchangeIDs filters tickers by comma-separated exchange ids.
+	ExchangeIDs string
+	// MaxPages is the maximum number of ticker pages(100 tickers per page) fetched. Default value: 10.
+	MaxPages uint
+	// IncludeUnhealthy keeps stale and anomalous tickers, they are dropped by default.
+	IncludeUnhealthy bool
+}
+
+// Venue is a trading pair on an exchange. DepthUp and DepthDown are the USD amounts needed to move the price up and
+// down by 2%.
+type Venue struct {
+	ExchangeID             string  `json:"exchange_id"`
+	ExchangeName           string  `json:"exchange_name"`
+	Base                   string  `json:"base"`
+	Target                 string  `json:"target"`
+	PriceUSD               float64 `json:"price_usd"`
+	VolumeUSD              float64 `json:"volume_usd"`
+	DepthUp                float64 `json:"depth_up"`
+	DepthDown              float64 `json:"depth_down"`
+	BidAskSpreadPercentage float64 `json:"bid_ask_spread_percentage"`
+}
+
+// ExchangeDepth is the depth of all venues of an exchange.
+type ExchangeDepth struct {
+	ExchangeID   string  `json:"exchange_id"`
+	ExchangeName string  `json:"exchange_name"`
+	Venues       int     `json:"venues"`
+	VolumeUSD    float64 `json:"volume_usd"`
+	DepthUp      float64 `json:"depth_up"`
+	DepthDown    float64 `json:"depth_down"`
+}
+
+// Report is the liquidity of a coin. Only venues reporting depth are included.
+type Report struct {
+	CoinID    string  `json:"coin_id"`
+	Venues    []Venue `json:"venues"`
+	DepthUp   float64 `json:"depth_up"`
+	DepthDown float64 `json:"depth_down"`
+	// Exchanges are ordered by total depth in descending order.
+	Exchanges []ExchangeDepth `json:"exchanges"`
+	// BestVenues maps upper-case target currencies into the venue with the largest total depth.
+	BestVenues map[string]Venue `json:"best_venues"`
+}
+
+// Allocation is the part of an order executed on a venue.
+type Allocation struct {
+	Venue   Venue   `json:"venue"`
+	SizeUSD float64 `json:"size_usd"`
+}
+
+// Estimate is the estimated cost to execute an order.
+type Estimate struct {
+	SizeUSD float64 `json:"size_usd"`
+	// SlippageUSD is the cost of moving the price through the order books.
+	SlippageUSD float64 `json:"slippage_usd"`
+	// SpreadUSD is the cost of crossing half of the bid ask spread.
+	SpreadUSD float64 `json:"spread_usd"`
+	TotalUSD  float64 `json:"total_usd"`
+	// CostPercentage is TotalUSD / SizeUSD in percent.
+	CostPercentage float64 `json:"cost_percentage"`
+	// ImpactPercentage is the final price move in percent.
+	ImpactPercentage float64 `json:"impact_percentage"`
+	// WithinDepth is false if the order is larger than the reported depth and the estimate is extrapolated.
+	WithinDepth bool         `json:"within_depth"`
+	Allocations []Allocation `json:"allocations"`
+}
+
+// Fetch pages the tickers of coin id with depth and builds its Report.
+func Fetch(ctx context.Context, client *coingecko.Client, id string, opts Options) (*Report, error) {
+	if id == "" {
+		return nil, fmt.Errorf("coin id should not be empty")
+	}
+	if opts.MaxPages == 0 {
+		opts.MaxPages = defaultMaxPages
+	}
+
+	var tickers []coingecko.TickersItem
+	for page := uint(1); page <= opts.MaxPages; page++ {
+		data, pageCount, err := client.GetCoinTickersByCoinID(ctx, id, opts.ExchangeIDs, false, page, "volume_desc", true)
+		if err != nil {
+			return nil, err
+		}
+		tickers = append(tickers, data.Tickers...)
+		if len(data.Tickers) == 0 || int(page) >= pageCount {
+			break
+		}
+	}
+	if !opts.IncludeUnhealthy {
+		tickers = coingecko.FilterTickers(tickers, coingecko.Healthy)
+	}
+	return NewReport(id, tickers), nil
+}
+
+// NewReport builds the Report of coin id from tickers fetched with depth. Tickers without depth are skipped.
+func NewReport(id string, tickers []coingecko.TickersItem) *Report {
+	report := &Report{CoinID: id, BestVenues: make(map[string]Venue)}
+	exchanges := make(map[string]*ExchangeDepth)
+	for i := range tickers {
+		t := &tickers[i]
+		if t.CostToMoveUpUsd <= 0 && t.CostToMoveDownUsd <= 0 {
+			continue
+		}
+		venue := Venue{
+			ExchangeID:             t.Market.Identifier,
+			ExchangeName:           t.Market.Name,
+			Base:                   t.Base,
+			Target:                 t.Target,
+			PriceUSD:               t.ConvertedLast["usd"],
+			VolumeUSD:              t.ConvertedVolume["usd"],
+			DepthUp:                t.CostToMoveUpUsd,
+			DepthDown:              t.CostToMoveDownUsd,
+			BidAskSpreadPercentage: t.BidAskSpreadPercentage,
+		}
+		report.Venues = append(report.Venues, venue)
+		report.DepthUp += venue.DepthUp
+		report.DepthDown += venue.DepthDown
+
+		exchange, ok := exchanges[venue.ExchangeID]
+		if !ok {
+			exchange = &ExchangeDepth{ExchangeID: venue.ExchangeID, ExchangeName: venue.ExchangeName}
+			exchanges[venue.ExchangeID] = exchange
+		}
+		exchange.Venues++
+		exchange.VolumeUSD += venue.VolumeUSD
+		exchange.DepthUp += venue.DepthUp
+		exchange.DepthDown += venue.DepthDown
+
+		target := strings.ToUpper(venue.Target)
+		if best, ok := report.BestVenues[target]; !ok || moreLiquid(venue, best) {
+			report.BestVenues[target] = venue
+		}
+	}
+
+	for _, exchange := range exchanges {
+		report.Exchanges = append(report.Exchanges, *exchange)
+	}
+	sort.Slice(report.Exchanges, func(i, j int) bool {
+		a, b := report.Exchanges[i], report.Exchanges[j]
+		if a.DepthUp+a.DepthDown != b.DepthUp+b.DepthDown {
+			return a.DepthUp+a.DepthDown > b.DepthUp+b.DepthDown
+		}
+		return a.ExchangeID < b.ExchangeID
+	})
+	return report
+}
+
+// EstimateCost estimates the cost to buy(or sell if buy is false) sizeUSD of the coin, split across venues.
+//
+// The price impact of a venue is assumed to grow linearly to 2% at its depth, so the cheapest split is
+// proportional to depth and the slippage of the whole order is 1% * sizeUSD^2 / total depth. It is a rough
+// estimate: depth is a snapshot and venues are assumed to be arbitraged to the same price.
+func (r *Report) EstimateCost(sizeUSD float64, buy bool) (*Estimate, error) {
+	if sizeUSD <= 0 {
+		return nil, fmt.Errorf("size should be greater than 0")
+	}
+
+	depthOf := func(v Venue) float64 {
+		if buy {
+			return v.DepthUp
+		}
+		return v.DepthDown
+	}
+	var total float64
+	for _, venue := range r.Venues {
+		total += depthOf(venue)
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("no venue reports depth")
+	}
+
+	estimate := &Estimate{
+		SizeUSD:          sizeUSD,
+		SlippageUSD:      depthPercentage / 2 / 100 * sizeUSD * sizeUSD / total,
+		ImpactPercentage: depthPercentage * sizeUSD / total,
+		WithinDepth:      sizeUSD <= total,
+	}
+	for _, venue := range r.Venues {
+		depth := depthOf(venue)
+		if depth <= 0 {
+			continue
+		}
+		size := sizeUSD * depth / total
+		estimate.SpreadUSD += size * venue.BidAskSpreadPercentage / 2 / 100
+		estimate.Allocations = append(estimate.Allocations, Allocation{Venue: venue, SizeUSD: size})
+	}
+	sort.SliceStable(estimate.Allocations, func(i, j int) bool {
+		return estimate.Allocations[i].SizeUSD > estimate.Allocations[j].SizeUSD
+	})
+	estimate.TotalUSD = estimate.SlippageUSD + estimate.SpreadUSD
+	estimate.CostPercentage = estimate.TotalUSD / sizeUSD * 100
+	return estimate, nil
+}
+
+// moreLiquid reports whether a has more total depth than b, ties are broken by volume.
+func moreLiquid(a, b Venue) bool {
+	da, db := a.DepthUp+a.DepthDown, b.DepthUp+b.DepthDown
+	if math.Abs(da-db) > 1e-9 {
+		return da > db
+	}
+	return a.VolumeUSD > b.VolumeUSD
+}
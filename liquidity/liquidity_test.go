@@ -0,0 +1,99 @@
+package liquidity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+const tickerFormat = `{"base":"BTC","target":"%s","market":{"name":"%s","identifier":"%s"},
+"converted_last":{"usd":40000},"converted_volume":{"usd":%d},"cost_to_move_up_usd":%d,"cost_to_move_down_usd":%d,
+"bid_ask_spread_percentage":0.1,"is_stale":%t}`
+
+func TestFetch(t *testing.T) {
+	pages := map[string]string{
+		"1": fmt.Sprintf(`{"name":"Bitcoin","tickers":[%s,%s]}`,
+			fmt.Sprintf(tickerFormat, "USDT", "Binance", "binance", 1000, 600000, 400000, false),
+			fmt.Sprintf(tickerFormat, "USD", "Coinbase", "gdax", 500, 200000, 300000, false)),
+		"2": fmt.Sprintf(`{"name":"Bitcoin","tickers":[%s,%s,%s]}`,
+			fmt.Sprintf(tickerFormat, "USDT", "OKX", "okex", 800, 150000, 150000, false),
+			fmt.Sprintf(tickerFormat, "USD", "Kraken", "kraken", 100, 0, 0, false),
+			fmt.Sprintf(tickerFormat, "USDT", "Stale", "stale", 100, 900000, 900000, true)),
+	}
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("depth") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Add("total", "150")
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}))
+	defer server.Close()
+	client := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+
+	report, err := Fetch(context.TODO(), client, "bitcoin", Options{})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("incorrect call number, wanted: 2, got: %d", calls)
+	}
+	if len(report.Venues) != 3 || report.DepthUp != 950000 || report.DepthDown != 850000 {
+		t.Fatalf("incorrect depth, got: %+v", report)
+	}
+	if report.Exchanges[0].ExchangeID != "binance" || report.Exchanges[2].ExchangeID != "okex" {
+		t.Fatalf("incorrect exchange order, got: %+v", report.Exchanges)
+	}
+	if report.BestVenues["USDT"].ExchangeID != "binance" || report.BestVenues["USD"].ExchangeID != "gdax" {
+		t.Fatalf("incorrect best venues, got: %+v", report.BestVenues)
+	}
+}
+
+func TestReport_EstimateCost(t *testing.T) {
+	tickers := []coingecko.TickersItem{
+		{Target: "USDT", CostToMoveUpUsd: 750000, CostToMoveDownUsd: 750000, BidAskSpreadPercentage: 0.2},
+		{Target: "USD", CostToMoveUpUsd: 250000, CostToMoveDownUsd: 250000},
+	}
+	tickers[0].Market.Identifier = "binance"
+	tickers[1].Market.Identifier = "gdax"
+	report := NewReport("bitcoin", tickers)
+	cases := []struct {
+		name             string
+		size             float64
+		wantedIsErr      bool
+		wantedTotal      float64
+		wantedImpact     float64
+		wantedWithin     bool
+		wantedFirstAlloc float64
+	}{
+		// slippage 1% * 100000^2 / 1000000 = 100, spread 75000 * 0.1% = 75.
+		{name: "within depth", size: 100000, wantedTotal: 175, wantedImpact: 0.2, wantedWithin: true, wantedFirstAlloc: 75000},
+		{name: "beyond depth", size: 2000000, wantedTotal: 41500, wantedImpact: 4, wantedFirstAlloc: 1500000},
+		{name: "invalid size", size: 0, wantedIsErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate, err := report.EstimateCost(tt.size, true)
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if math.Abs(estimate.TotalUSD-tt.wantedTotal) > 1e-6 || math.Abs(estimate.ImpactPercentage-tt.wantedImpact) > 1e-9 ||
+				estimate.WithinDepth != tt.wantedWithin || math.Abs(estimate.Allocations[0].SizeUSD-tt.wantedFirstAlloc) > 1e-6 {
+				t.Fatalf("incorrect estimate, got: %+v", estimate)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+// Package listing detects newly listed coins on CoinGecko and new pools on GeckoTerminal.
+package listing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+const (
+	// DefaultCoinInterval matches the update frequency of ListLatest200Coins API.
+	DefaultCoinInterval = 30 * time.Second
+	// DefaultPoolInterval keeps the pool polls within the GeckoTerminal rate limit for a few networks.
+	DefaultPoolInterval = 60 * time.Second
+
+	defaultBuffer = 64
+
+	coinWindow = 200
+	poolWindow = 20
+
+	// SourceCoins is the source of listings returned by ListLatest200Coins API.
+	SourceCoins = "coins"
+	// SourceAllNetworks is the source of listings returned by GetLatest20PoolsOnAllNetworks API.
+	SourceAllNetworks = "pools"
+)
+
+// SourceNetwork returns the source of listings returned by GetLatest20PoolsOnOneNetwork API for network.
+func SourceNetwork(network string) string {
+	return "pools:" + network
+}
+
+// EventType is the type of Event.
+type EventType int
+
+const (
+	// EventCoin is emitted once for every new coin.
+	EventCoin EventType = iota
+	// EventPool is emitted once for every new pool.
+	EventPool
+	// EventGap is emitted when a whole window of a source is new since the previous poll, listings older than the
+	// window may have been missed. Poll more often to avoid it.
+	EventGap
+	// EventError is emitted when a poll fails, the detector keeps polling.
+	EventError
+)
+
+// Event is emitted by Detector. Coin is set for EventCoin, Pool and Network for EventPool.
+type Event struct {
+	Type    EventType                             `json:"type"`
+	Source  string                                `json:"source"`
+	Time    time.Time                             `json:"time"`
+	Coin    *coingecko.ListLatest200CoinsResponse `json:"coin,omitempty"`
+	Pool    *geckoterminal.PoolDataItem           `json:"pool,omitempty"`
+	Network string                                `json:"network,omitempty"`
+	Err     error                                 `json:"-"`
+}
+
+// Config configures Detector.
+type Config struct {
+	// Coins enables polling ListLatest200Coins API, which requires a paid plan.
+	Coins bool
+	// Networks are polled with GetLatest20PoolsOnOneNetwork API.
+	Networks []string
+	// AllNetworks enables polling GetLatest20PoolsOnAllNetworks API.
+	AllNetworks bool
+	// CoinInterval is the interval between coin polls. Default value: DefaultCoinInterval.
+	CoinInterval time.Duration
+	// PoolInterval is the interval between pool polls. Default value: DefaultPoolInterval.
+	PoolInterval time.Duration
+	// Store remembers seen listings. Default value: a new MemoryStore.
+	Store Store
+	// EmitInitial emits the listings of the first poll of a source. By default they are only marked as seen, so
+	// that the detector reports listings created after it started.
+	EmitInitial bool
+	// Buffer is the buffer size of the event channel. Default value: 64.
+	Buffer int
+}
+
+// Detector polls the latest coins and pools and emits every new listing exactly once. Coins and pools are keyed
+// by coin id and GeckoTerminal pool id, so a pool returned by several sources is emitted once.
+type Detector struct {
+	cg     *coingecko.Client
+	gt     *geckoterminal.Client
+	cfg    Config
+	events chan Event
+	now    func() time.Time
+}
+
+// item is a listing in a polled window.
+type item struct {
+	key     string
+	created time.Time
+	event   Event
+}
+
+// New creates a Detector. cg may be nil if coins are not polled, gt may be nil if pools are not polled.
+func New(cg *coingecko.Client, gt *geckoterminal.Client, cfg Config) (*Detector, error) {
+	if !cfg.Coins && len(cfg.Networks) == 0 && !cfg.AllNetworks {
+		return nil, fmt.Errorf("at least one source should be enabled")
+	}
+	if cfg.Coins && cg == nil {
+		return nil, fmt.Errorf("coingecko client should not be nil when polling coins")
+	}
+	if (len(cfg.Networks) != 0 || cfg.AllNetworks) && gt == nil {
+		return nil, fmt.Errorf("geckoterminal client should not be nil when polling pools")
+	}
+	if cfg.CoinInterval <= 0 {
+		cfg.CoinInterval = DefaultCoinInterval
+	}
+	if cfg.PoolInterval <= 0 {
+		cfg.PoolInterval = DefaultPoolInterval
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore()
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = defaultBuffer
+	}
+	return &Detector{cg: cg, gt: gt, cfg: cfg, events: make(chan Event, cfg.Buffer), now: time.Now}, nil
+}
+
+// Events returns the event channel, it is closed when Run returns.
+func (d *Detector) Events() <-chan Event {
+	return d.events
+}
+
+// Run polls immediately and then on the configured intervals until ctx is done. It should be called once.
+func (d *Detector) Run(ctx context.Context) error {
+	defer close(d.events)
+
+	var coinTick, poolTick <-chan time.Time
+	if d.cfg.Coins {
+		ticker := time.NewTicker(d.cfg.CoinInterval)
+		defer ticker.Stop()
+		coinTick = ticker.C
+		d.pollCoins(ctx)
+	}
+	if len(d.cfg.Networks) != 0 || d.cfg.AllNetworks {
+		ticker := time.NewTicker(d.cfg.PoolInterval)
+		defer ticker.Stop()
+		poolTick = ticker.C
+		d.pollPools(ctx)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-coinTick:
+			d.pollCoins(ctx)
+		case <-poolTick:
+			d.pollPools(ctx)
+		}
+	}
+}
+
+func (d *Detector) pollCoins(ctx context.Context) {
+	data, err := d.cg.ListLatest200Coins(ctx)
+	if err != nil {
+		d.fail(ctx, SourceCoins, err)
+		return
+	}
+
+	items := make([]item, 0, len(*data))
+	for i := range *data {
+		coin := (*data)[i]
+		items = append(items, item{
+			key:     "coin:" + coin.ID,
+			created: time.Unix(coin.ActivatedAt, 0).UTC(),
+			event:   Event{Type: EventCoin, Source: SourceCoins, Coin: &coin},
+		})
+	}
+	d.process(ctx, SourceCoins, coinWindow, items)
+}
+
+func (d *Detector) pollPools(ctx context.Context) {
+	for _, network := range d.cfg.Networks {
+		source := SourceNetwork(network)
+		data, err := d.gt.GetLatest20PoolsOnOneNetwork(ctx, network, nil)
+		if err != nil {
+			d.fail(ctx, source, err)
+			continue
+		}
+		d.process(ctx, source, poolWindow, poolItems(source, network, data.Data))
+	}
+	if d.cfg.AllNetworks {
+		data, err := d.gt.GetLatest20PoolsOnAllNetworks(ctx, nil)
+		if err != nil {
+			d.fail(ctx, SourceAllNetworks, err)
+			return
+		}
+		d.process(ctx, SourceAllNetworks, poolWindow, poolItems(SourceAllNetworks, "", data.Data))
+	}
+}
+
+func poolItems(source, network string, pools []geckoterminal.PoolDataItem) []item {
+	items := make([]item, 0, len(pools))
+	for i := range pools {
+		pool := pools[i]
+		var created time.Time
+		if pool.Attributes.PoolCreatedAt != nil {
+			created = *pool.Attributes.PoolCreatedAt
+		}
+		poolNetwork := network
+		if poolNetwork == "" {
+			poolNetwork = pool.Relationships["network"]["data"].ID
+		}
+		items = append(items, item{
+			key:     "pool:" + pool.ID,
+			created: created,
+			event:   Event{Type: EventPool, Source: source, Pool: &pool, Network: poolNetwork},
+		})
+	}
+	return items
+}
+
+// process emits the unseen items of a polled window from the oldest to the newest and marks them as seen.
+func (d *Detector) process(ctx context.Context, source string, window int, items []item) {
+	// the marker records that the source has been polled, it survives restarts with a persistent store.
+	marker := "source:" + source
+	primed, err := d.cfg.Store.Seen(ctx, marker)
+	if err != nil {
+		d.fail(ctx, source, err)
+		return
+	}
+
+	var unseen []item
+	for _, it := range items {
+		seen, err := d.cfg.Store.Seen(ctx, it.key)
+		if err != nil {
+			d.fail(ctx, source, err)
+			return
+		}
+		if !seen {
+			unseen = append(unseen, it)
+		}
+	}
+	sort.SliceStable(unseen, func(i, j int) bool {
+		return unseen[i].created.Before(unseen[j].created)
+	})
+
+	emit := primed || d.cfg.EmitInitial
+	if primed && len(items) >= window && len(unseen) == len(items) {
+		slog.Warn("listing window moved past the previous poll, listings may have been missed", "listing_source", source)
+		d.emit(ctx, Event{Type: EventGap, Source: source, Time: d.now()})
+	}
+	for _, it := range unseen {
+		if emit {
+			it.event.Time = d.now()
+			if !d.emit(ctx, it.event) {
+				return
+			}
+		}
+		if err = d.cfg.Store.MarkSeen(ctx, it.key); err != nil {
+			d.fail(ctx, source, err)
+			return
+		}
+	}
+	if !primed {
+		if err = d.cfg.Store.MarkSeen(ctx, marker); err != nil {
+			d.fail(ctx, source, err)
+		}
+	}
+}
+
+func (d *Detector) fail(ctx context.Context, source string, err error) {
+	slog.Error("failed to poll listings", "listing_source", source, "error", err)
+	d.emit(ctx, Event{Type: EventError, Source: source, Time: d.now(), Err: err})
+}
+
+// emit sends event unless ctx is done, it reports whether the event is sent.
+func (d *Detector) emit(ctx context.Context, event Event) bool {
+	select {
+	case d.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,129 @@
+package listing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+// poolsWindow returns the latest pools response of the pools numbered [from, to), newest first.
+func poolsWindow(from, to int) []byte {
+	var data geckoterminal.PoolsResponse
+	base := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	for i := to - 1; i >= from; i-- {
+		created := base.Add(time.Duration(i) * time.Minute)
+		var pool geckoterminal.PoolDataItem
+		pool.ID = fmt.Sprintf("eth_0x%d", i)
+		pool.Attributes.PoolCreatedAt = &created
+		data.Data = append(data.Data, pool)
+	}
+	resp, _ := json.Marshal(data)
+	return resp
+}
+
+func setup(t *testing.T, windows [][2]int, store Store, emitInitial bool) *Detector {
+	t.Helper()
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		window := windows[min(calls, len(windows)-1)]
+		calls++
+		_, _ = w.Write(poolsWindow(window[0], window[1]))
+	}))
+	t.Cleanup(server.Close)
+
+	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithBaseURL(server.URL+"/api/v2"))
+	d, err := New(nil, gt, Config{Networks: []string{"eth"}, Store: store, EmitInitial: emitInitial, Buffer: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func poll(d *Detector, times int) []Event {
+	for i := 0; i < times; i++ {
+		d.pollPools(context.TODO())
+	}
+	var events []Event
+	for {
+		select {
+		case event := <-d.events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func summary(events []Event) []string {
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		switch event.Type {
+		case EventPool:
+			result = append(result, event.Pool.ID)
+		case EventGap:
+			result = append(result, "gap")
+		default:
+			result = append(result, fmt.Sprintf("unexpected %d", event.Type))
+		}
+	}
+	return result
+}
+
+func TestDetector_Pools(t *testing.T) {
+	cases := []struct {
+		name        string
+		windows     [][2]int
+		emitInitial bool
+		wanted      []string
+	}{
+		{
+			name:    "new pools once in chronological order",
+			windows: [][2]int{{0, 20}, {2, 22}, {2, 22}},
+			wanted:  []string{"eth_0x20", "eth_0x21"},
+		},
+		{
+			name:        "emit initial window",
+			windows:     [][2]int{{0, 20}, {1, 21}},
+			emitInitial: true,
+			wanted:      append(ids(0, 20), "eth_0x20"),
+		},
+		{
+			name:    "gap when the whole window is new",
+			windows: [][2]int{{0, 20}, {30, 50}},
+			wanted:  append([]string{"gap"}, ids(30, 50)...),
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			d := setup(t, tt.windows, NewMemoryStore(), tt.emitInitial)
+			if result := summary(poll(d, len(tt.windows))); !reflect.DeepEqual(result, tt.wanted) {
+				t.Fatalf("incorrect events, wanted: %v, got: %v", tt.wanted, result)
+			}
+		})
+	}
+}
+
+func TestDetector_ResumeWithStore(t *testing.T) {
+	store := NewMemoryStore()
+	_ = poll(setup(t, [][2]int{{0, 20}}, store, false), 1)
+
+	d := setup(t, [][2]int{{1, 21}}, store, false)
+	if result := summary(poll(d, 1)); !reflect.DeepEqual(result, []string{"eth_0x20"}) {
+		t.Fatalf("incorrect events, got: %v", result)
+	}
+}
+
+func ids(from, to int) []string {
+	var result []string
+	for i := from; i < to; i++ {
+		result = append(result, fmt.Sprintf("eth_0x%d", i))
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package listing
+
+import (
+	"context"
+	"sync"
+)
+
+// Store remembers the keys seen by Detector. Implementations backed by a database or a file make the detector
+// resume after a restart without emitting listings again.
+type Store interface {
+	// Seen reports whether key has been marked as seen.
+	Seen(ctx context.Context, key string) (bool, error)
+	// MarkSeen marks key as seen.
+	MarkSeen(ctx context.Context, key string) error
+}
+
+// MemoryStore is an in-memory Store, it is safe for concurrent use.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{seen: make(map[string]struct{})}
+}
+
+// Seen implements Store.
+func (s *MemoryStore) Seen(_ context.Context, key string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.seen[key]
+	return ok, nil
+}
+
+// MarkSeen implements Store.
+func (s *MemoryStore) MarkSeen(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[key] = struct{}{}
+	return nil
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bufdata/coingecko-api/util"
+)
+
+// RateLimitWaitBuckets are the histogram buckets of rate limiter waits in seconds.
+var RateLimitWaitBuckets = []float64{0, 0.1, 0.5, 1, 2, 5, 10, 30, 60}
+
+// ClientMetrics measures the requests of API clients:
+//
+//   - coingecko_client_requests_total{api, route, code}: finished requests by status code, code is "error" for
+//     requests failed without response.
+//   - coingecko_client_request_duration_seconds{api, route}: request durations, including retries and rate limiter
+//     waits.
+//   - coingecko_client_retries_total{api, route}: retried requests, see util.Retry.
+//   - coingecko_client_rate_limit_wait_seconds{api}: how long requests wait for the rate limiter of the client.
+type ClientMetrics struct {
+	requests      *CounterVec
+	duration      *HistogramVec
+	retries       *CounterVec
+	rateLimitWait *HistogramVec
+}
+
+// NewClientMetrics registers the client metrics in r.
+func NewClientMetrics(r *Registry) *ClientMetrics {
+	return &ClientMetrics{
+		requests: r.NewCounterVec("coingecko_client_requests_total",
+			"Number of API requests by status code.", "api", "route", "code"),
+		duration: r.NewHistogramVec("coingecko_client_request_duration_seconds",
+			"Duration of API requests, including retries and rate limiter waits.", nil, "api", "route"),
+		retries: r.NewCounterVec("coingecko_client_retries_total",
+			"Number of retried API requests.", "api", "route"),
+		rateLimitWait: r.NewHistogramVec("coingecko_client_rate_limit_wait_seconds",
+			"Time API requests wait for the client rate limiter.", RateLimitWaitBuckets, "api"),
+	}
+}
+
+// Middleware returns a util.Middleware which measures the requests of a client. The api label is the API of the call,
+// coingecko or geckoterminal, and the route label is its path template, e.g. /coins/{}/tickers.
+//
+// Pass it before util.Retry to WithMiddleware of the client, so that it sees the retries:
+//
+//	client := coingecko.NewCoinGecko(apiKey, false, nil, coingecko.WithMiddleware(m.Middleware(),
+//		util.Retry(3, time.Second)))
+func (m *ClientMetrics) Middleware() util.Middleware {
+	return func(next util.SendFunc) util.SendFunc {
+		return func(ctx context.Context, call util.CallInfo) ([]byte, http.Header, error) {
+			api, route := call.API, call.Template()
+			ctx = util.WithRequestTrace(ctx, &util.RequestTrace{
+				RateLimitWait: func(wait time.Duration) { m.rateLimitWait.Observe(wait.Seconds(), api) },
+				Retry:         func(int, error) { m.retries.Inc(api, route) },
+			})
+
+			start := time.Now()
+			data, header, err := next(ctx, call)
+			m.duration.Observe(time.Since(start).Seconds(), api, route)
+			code := "error"
+			if status := util.StatusCode(err); status != 0 {
+				code = strconv.Itoa(status)
+			}
+			m.requests.Inc(api, route, code)
+			return data, header, err
+		}
+	}
+}
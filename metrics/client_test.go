@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+func TestClientMetrics_Middleware(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/ping") && calls == 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case strings.HasSuffix(r.URL.Path, "/ping"):
+			_, _ = w.Write([]byte(`{"gecko_says":"(V3) To the Moon!"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"coin not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	r := NewRegistry()
+	m := NewClientMetrics(r)
+	client := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"),
+		coingecko.WithRateLimit(6000), coingecko.WithMiddleware(m.Middleware(),
+			util.Retry(1, time.Millisecond)))
+	if _, err := client.Ping(context.TODO()); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if _, err := client.GetNFTDataByNFTID(context.TODO(), "missing"); err == nil {
+		t.Fatal("error should not be nil")
+	}
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	for _, wanted := range []string{
+		`coingecko_client_requests_total{api="coingecko",route="/nfts/{}",code="404"} 1`,
+		`coingecko_client_requests_total{api="coingecko",route="/ping",code="200"} 1`,
+		`coingecko_client_retries_total{api="coingecko",route="/ping"} 1`,
+		`coingecko_client_request_duration_seconds_count{api="coingecko",route="/ping"} 1`,
+		`coingecko_client_rate_limit_wait_seconds_count{api="coingecko"} 3`,
+	} {
+		if !strings.Contains(buf.String(), wanted) {
+			t.Fatalf("output should contain %s, got:\n%s", wanted, buf.String())
+		}
+	}
+}
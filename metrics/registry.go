@@ -0,0 +1,300 @@
+// Package metrics exposes metrics in the Prometheus text exposition format without depending on the Prometheus
+// client library, and instruments the API clients with them.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+
+	// contentType is the content type of the text exposition format.
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultBuckets are the histogram buckets of request durations in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Registry holds metric families and writes them in the text exposition format. It is an http.Handler serving
+// /metrics.
+//
+// Registering a metric with an invalid or duplicate name panics, so does using a metric with the wrong number of
+// label values: both are programming errors.
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+	names    map[string]bool
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// family is a metric with all of its series.
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is a metric of a label value set. value is used by counters and gauges, counts, sum and count by histograms.
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	sum         float64
+	count       uint64
+}
+
+func (r *Registry) register(name, help, typ string, buckets []float64, labels []string) *family {
+	if !validName(name, false) {
+		panic(fmt.Sprintf("metrics: invalid metric name %q", name))
+	}
+	for _, label := range labels {
+		if !validName(label, true) || label == "le" {
+			panic(fmt.Sprintf("metrics: invalid label name %q of %s", label, name))
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: duplicate metric %s", name))
+	}
+	r.names[name] = true
+	f := &family{name: name, help: help, typ: typ, labels: labels, buckets: buckets, series: make(map[string]*series)}
+	r.families = append(r.families, f)
+	return f
+}
+
+// get returns the series of labelValues, it is created if it does not exist. f.mu must be held.
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s has %d labels, got %d values", f.name, len(f.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.typ == histogramType {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	f *family
+}
+
+// NewCounterVec registers a counter.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{f: r.register(name, help, counterType, nil, labels)}
+}
+
+// Inc adds 1 to the counter of labelValues.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds value, which must not be negative, to the counter of labelValues.
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.f.name))
+	}
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.get(labelValues).value += value
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	f *family
+}
+
+// NewGaugeVec registers a gauge.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{f: r.register(name, help, gaugeType, nil, labels)}
+}
+
+// Set sets the gauge of labelValues to value.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(labelValues).value = value
+}
+
+// Delete removes the gauge of labelValues, so that it is no longer exposed.
+func (g *GaugeVec) Delete(labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	delete(g.f.series, strings.Join(labelValues, "\xff"))
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	f *family
+}
+
+// NewHistogramVec registers a histogram with the upper bounds of buckets, nil means DefaultBuckets. The +Inf bucket is
+// implicit.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	if n := len(buckets); n > 0 && math.IsInf(buckets[n-1], 1) {
+		buckets = buckets[:n-1]
+	}
+	return &HistogramVec{f: r.register(name, help, histogramType, buckets, labels)}
+}
+
+// Observe adds value to the histogram of labelValues.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+	s := h.f.get(labelValues)
+	for i, bound := range h.f.buckets {
+		if value <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+// Write writes all metrics to w in the text exposition format. Metrics are written in the order they are registered,
+// series of a metric are sorted by their label values.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]*family(nil), r.families...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP serves the metrics.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_ = r.Write(w)
+}
+
+func (f *family) write(w *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+	for _, key := range keys {
+		s := f.series[key]
+		if f.typ != histogramType {
+			writeSample(w, f.name, f.labels, s.labelValues, "", s.value)
+			continue
+		}
+		var cumulative uint64
+		for i, bound := range f.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, f.name+"_bucket", f.labels, s.labelValues, formatFloat(bound), float64(cumulative))
+		}
+		writeSample(w, f.name+"_bucket", f.labels, s.labelValues, "+Inf", float64(s.count))
+		writeSample(w, f.name+"_sum", f.labels, s.labelValues, "", s.sum)
+		writeSample(w, f.name+"_count", f.labels, s.labelValues, "", float64(s.count))
+	}
+}
+
+// writeSample writes a sample line, le is the bucket label of histograms and empty otherwise.
+func writeSample(w *bufio.Writer, name string, labels, labelValues []string, le string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || le != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", label, escapeLabelValue(labelValues[i]))
+		}
+		if le != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "le=\"%s\"", le)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// formatFloat formats v as a sample value, infinities are +Inf and -Inf and not a number is NaN.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// validName tells whether name is a valid metric name, or a valid label name if label is true.
+func validName(name string, label bool) bool {
+	if name == "" || (label && strings.HasPrefix(name, "__")) {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r == ':' && !label:
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
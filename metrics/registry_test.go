@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	r := NewRegistry()
+	price := r.NewGaugeVec("coingecko_price", "Price of a coin.", "coin", "vs_currency")
+	requests := r.NewCounterVec("requests_total", "Number of requests.\nBy code.")
+	duration := r.NewHistogramVec("duration_seconds", "Durations.", []float64{1, 0.5}, "route")
+
+	price.Set(2200, "ethereum", "usd")
+	price.Set(42000, "bitcoin", "usd")
+	price.Set(math.Inf(1), `we"ird\`, "usd")
+	price.Delete(`we"ird\`, "usd")
+	requests.Inc()
+	requests.Add(2)
+	duration.Observe(0.3, "/ping")
+	duration.Observe(0.7, "/ping")
+	duration.Observe(3, "/ping")
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	wanted := `# HELP coingecko_price Price of a coin.
+# TYPE coingecko_price gauge
+coingecko_price{coin="bitcoin",vs_currency="usd"} 42000
+coingecko_price{coin="ethereum",vs_currency="usd"} 2200
+# HELP requests_total Number of requests.\nBy code.
+# TYPE requests_total counter
+requests_total 3
+# HELP duration_seconds Durations.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/ping",le="0.5"} 1
+duration_seconds_bucket{route="/ping",le="1"} 2
+duration_seconds_bucket{route="/ping",le="+Inf"} 3
+duration_seconds_sum{route="/ping"} 4
+duration_seconds_count{route="/ping"} 3
+`
+	if buf.String() != wanted {
+		t.Fatalf("incorrect output, wanted:\n%s\ngot:\n%s", wanted, buf.String())
+	}
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if recorder.Header().Get("Content-Type") != contentType || recorder.Body.String() != wanted {
+		t.Fatalf("incorrect response: %s %s", recorder.Header().Get("Content-Type"), recorder.Body.String())
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	if s := escapeLabelValue("a\"b\\c\nd"); s != `a\"b\\c\nd` {
+		t.Fatalf("incorrect escaped value: %s", s)
+	}
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	cases := []struct {
+		name string
+		fn   func(r *Registry)
+	}{
+		{name: "invalid name", fn: func(r *Registry) { r.NewGaugeVec("coingecko-price", "") }},
+		{name: "invalid label", fn: func(r *Registry) { r.NewGaugeVec("price", "", "le") }},
+		{name: "duplicate", fn: func(r *Registry) { r.NewGaugeVec("price", ""); r.NewCounterVec("price", "") }},
+		{name: "label values", fn: func(r *Registry) { r.NewGaugeVec("price", "", "coin").Set(1) }},
+		{name: "negative counter", fn: func(r *Registry) { r.NewCounterVec("total", "").Add(-1) }},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("it should panic")
+				}
+			}()
+			tt.fn(NewRegistry())
+		})
+	}
+}
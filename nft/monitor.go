@@ -0,0 +1,396 @@
+// Package nft monitors the floor prices of NFT collections across marketplaces and keeps their floor history.
+package nft
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	// DefaultInterval matches the update frequency of GetNFTDataByNFTID API.
+	DefaultInterval = 60 * time.Second
+
+	defaultBuffer = 64
+	// defaultMaxHistory is the default number of floor points kept per collection.
+	defaultMaxHistory = 10000
+)
+
+// EventType is the type of Event.
+type EventType int
+
+const (
+	// EventFloor is emitted for every polled floor of a collection.
+	EventFloor EventType = iota
+	// EventMove is emitted when the floor moved by at least Config.MovePercent since the last move event(or the first
+	// floor).
+	EventMove
+	// EventSpread is emitted when the floors of the marketplaces of a collection start to differ by at least
+	// Config.SpreadPercent, it is emitted again after the spread narrows below Config.SpreadPercent.
+	EventSpread
+	// EventError is emitted when a poll fails, the monitor keeps polling.
+	EventError
+)
+
+// String returns the name of t.
+func (t EventType) String() string {
+	switch t {
+	case EventFloor:
+		return "floor"
+	case EventMove:
+		return "move"
+	case EventSpread:
+		return "spread"
+	case EventError:
+		return "error"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+}
+
+// Point is the floor price of a collection at a time.
+type Point struct {
+	Time   time.Time `json:"time"`
+	Native float64   `json:"native"`
+	USD    float64   `json:"usd"`
+}
+
+// MarketplaceFloor is the floor price of a collection on one marketplace, in the native currency of the collection.
+type MarketplaceFloor struct {
+	MarketplaceID string     `json:"marketplace_id"`
+	Floor         float64    `json:"floor"`
+	Volume24h     float64    `json:"volume_24h"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// Event is emitted by Monitor.
+type Event struct {
+	Type         EventType `json:"type"`
+	CollectionID string    `json:"collection_id"`
+	Time         time.Time `json:"time"`
+	// Floor is the floor price of the collection, Floor.Time is the time of the poll.
+	Floor          Point  `json:"floor"`
+	NativeCurrency string `json:"native_currency,omitempty"`
+	// Reference is the floor of the last EventMove(or the first floor) for EventMove.
+	Reference *Point `json:"reference,omitempty"`
+	// ChangePercent is the change from Reference for EventMove and the spread between the highest and the lowest
+	// marketplace floor for EventSpread.
+	ChangePercent float64 `json:"change_percent,omitempty"`
+	// Marketplaces are ordered by floor in ascending order, they are only available if Config.Marketplaces is set.
+	Marketplaces []MarketplaceFloor `json:"marketplaces,omitempty"`
+	Err          error              `json:"-"`
+}
+
+// Config configures Monitor.
+type Config struct {
+	// Collections are the monitored NFT collection ids.
+	Collections []string
+	// Interval is the interval between polls. Default value: DefaultInterval.
+	Interval time.Duration
+	// MovePercent enables EventMove if it is greater than 0.
+	MovePercent float64
+	// MoveInUSD detects moves of the USD floor instead of the native floor.
+	MoveInUSD bool
+	// Marketplaces fetches marketplace floors with GetNFTTickersByNFTID API, it requires a paid plan.
+	Marketplaces bool
+	// SpreadPercent enables EventSpread if it is greater than 0 and Marketplaces is set.
+	SpreadPercent float64
+	// BackfillDays backfills the history from GetMarketChartByNFTID API when Run starts, e.g. 14 or max. It requires a
+	// paid plan, the history is not backfilled if it is empty.
+	BackfillDays string
+	// MaxHistory is the maximum number of floor points kept per collection. Default value: 10000.
+	MaxHistory int
+	// Buffer is the buffer size of the event channel. Default value: 64.
+	Buffer int
+}
+
+// Monitor polls the floor prices of NFT collections, emits events and records the floor history.
+type Monitor struct {
+	client *coingecko.Client
+	cfg    Config
+	events chan Event
+	now    func() time.Time
+
+	mu      sync.RWMutex
+	history map[string][]Point
+	refs    map[string]Point
+	// spreads records the collections whose marketplace spread is at least Config.SpreadPercent.
+	spreads map[string]bool
+}
+
+// New creates a Monitor.
+func New(client *coingecko.Client, cfg Config) (*Monitor, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client should not be nil")
+	}
+	if len(cfg.Collections) == 0 {
+		return nil, fmt.Errorf("collections should not be empty")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.MaxHistory <= 0 {
+		cfg.MaxHistory = defaultMaxHistory
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = defaultBuffer
+	}
+
+	return &Monitor{
+		client:  client,
+		cfg:     cfg,
+		events:  make(chan Event, cfg.Buffer),
+		now:     time.Now,
+		history: make(map[string][]Point),
+		refs:    make(map[string]Point),
+		spreads: make(map[string]bool),
+	}, nil
+}
+
+// Events returns the event channel, it is closed when Run returns.
+func (m *Monitor) Events() <-chan Event {
+	return m.events
+}
+
+// History returns a copy of the floor history of collection id in time order.
+func (m *Monitor) History(id string) []Point {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Point(nil), m.history[id]...)
+}
+
+// Run backfills the history if Config.BackfillDays is set, polls immediately and then every Config.Interval until
+// ctx is done. It should be called once.
+func (m *Monitor) Run(ctx context.Context) error {
+	defer close(m.events)
+
+	if m.cfg.BackfillDays != "" {
+		for _, id := range m.cfg.Collections {
+			if err := m.Backfill(ctx, id, m.cfg.BackfillDays); err != nil {
+				m.emit(ctx, Event{Type: EventError, CollectionID: id, Time: m.now(), Err: err})
+			}
+		}
+	}
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	m.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			m.poll(ctx)
+		}
+	}
+}
+
+// Backfill merges the floor prices of collection id over the last days from GetMarketChartByNFTID API into the
+// history. Points already in the history are kept.
+func (m *Monitor) Backfill(ctx context.Context, id, days string) error {
+	data, err := m.client.GetMarketChartByNFTID(ctx, id, days)
+	if err != nil {
+		slog.Error("failed to backfill nft floor history", "error", err)
+		return err
+	}
+	points := FromMarketChart(data)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.history[id] = m.trim(merge(m.history[id], points))
+	return nil
+}
+
+func (m *Monitor) poll(ctx context.Context) {
+	for _, id := range m.cfg.Collections {
+		if err := m.pollCollection(ctx, id); err != nil {
+			m.emit(ctx, Event{Type: EventError, CollectionID: id, Time: m.now(), Err: err})
+		}
+	}
+}
+
+func (m *Monitor) pollCollection(ctx context.Context, id string) error {
+	data, err := m.client.GetNFTDataByNFTID(ctx, id)
+	if err != nil {
+		slog.Error("failed to poll nft floor price", "error", err)
+		return err
+	}
+
+	var marketplaces []MarketplaceFloor
+	if m.cfg.Marketplaces {
+		tickers, err := m.client.GetNFTTickersByNFTID(ctx, id)
+		if err != nil {
+			slog.Error("failed to poll nft marketplace floors", "error", err)
+			return err
+		}
+		marketplaces = FromTickers(tickers.Tickers)
+	}
+
+	m.observe(ctx, id, data.NativeCurrency, Point{
+		Time:   m.now().UTC(),
+		Native: data.FloorPrice.NativeCurrency,
+		USD:    data.FloorPrice.USD,
+	}, marketplaces)
+	return nil
+}
+
+// observe records a new floor of collection id and emits the resulting events.
+func (m *Monitor) observe(ctx context.Context, id, nativeCurrency string, floor Point, marketplaces []MarketplaceFloor) {
+	m.mu.Lock()
+	m.history[id] = m.trim(append(m.history[id], floor))
+	ref, seen := m.refs[id]
+	if !seen {
+		ref = floor
+		m.refs[id] = floor
+	}
+	var moved float64
+	if m.cfg.MovePercent > 0 {
+		if m.cfg.MoveInUSD {
+			moved = util.PercentChange(ref.USD, floor.USD)
+		} else {
+			moved = util.PercentChange(ref.Native, floor.Native)
+		}
+		if math.Abs(moved) >= m.cfg.MovePercent {
+			m.refs[id] = floor
+		}
+	}
+	spread, hasSpread := Spread(marketplaces)
+	spreadStarted := false
+	if hasSpread && m.cfg.SpreadPercent > 0 {
+		wide := spread >= m.cfg.SpreadPercent
+		spreadStarted = wide && !m.spreads[id]
+		m.spreads[id] = wide
+	}
+	m.mu.Unlock()
+
+	event := Event{CollectionID: id, Time: floor.Time, Floor: floor, NativeCurrency: nativeCurrency,
+		Marketplaces: marketplaces}
+	m.emit(ctx, withType(event, EventFloor))
+	if m.cfg.MovePercent > 0 && math.Abs(moved) >= m.cfg.MovePercent {
+		move := withType(event, EventMove)
+		move.Reference = &ref
+		move.ChangePercent = moved
+		m.emit(ctx, move)
+	}
+	if spreadStarted {
+		event = withType(event, EventSpread)
+		event.ChangePercent = spread
+		m.emit(ctx, event)
+	}
+}
+
+// trim drops the oldest points beyond Config.MaxHistory.
+func (m *Monitor) trim(points []Point) []Point {
+	if len(points) > m.cfg.MaxHistory {
+		points = append([]Point(nil), points[len(points)-m.cfg.MaxHistory:]...)
+	}
+	return points
+}
+
+// emit sends event unless ctx is done.
+func (m *Monitor) emit(ctx context.Context, event Event) {
+	select {
+	case m.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// FromMarketChart converts the floor prices returned by GetMarketChartByNFTID or GetMarketChartByNFTContractAddress
+// API into points in time order. Points only available in one currency are kept with 0 in the other.
+func FromMarketChart(data *coingecko.NFTsIDMarketChartResponse) []Point {
+	byTime := make(map[int64]*Point)
+	get := func(ms float64) *Point {
+		key := int64(ms)
+		p, ok := byTime[key]
+		if !ok {
+			p = &Point{Time: time.UnixMilli(key).UTC()}
+			byTime[key] = p
+		}
+		return p
+	}
+	for _, item := range data.FloorPriceNative {
+		get(item[0]).Native = item[1]
+	}
+	for _, item := range data.FloorPriceUSD {
+		get(item[0]).USD = item[1]
+	}
+
+	points := make([]Point, 0, len(byTime))
+	for _, p := range byTime {
+		points = append(points, *p)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+	return points
+}
+
+// FromTickers converts the tickers returned by GetNFTTickersByNFTID API into marketplace floors ordered by floor in
+// ascending order. Marketplaces without floor are skipped.
+func FromTickers(tickers []coingecko.NFTsIDTickersItem) []MarketplaceFloor {
+	floors := make([]MarketplaceFloor, 0, len(tickers))
+	for _, t := range tickers {
+		if t.FloorPriceInNativeCurrency <= 0 {
+			continue
+		}
+		floor := MarketplaceFloor{
+			MarketplaceID: t.NFTMarketplaceID,
+			Floor:         t.FloorPriceInNativeCurrency,
+			Volume24h:     t.H24VolumeInNativeCurrency,
+		}
+		// updated_at is optional, a malformed one is left nil.
+		if updatedAt, err := time.Parse(time.RFC3339, t.UpdatedAt); err == nil {
+			floor.UpdatedAt = &updatedAt
+		}
+		floors = append(floors, floor)
+	}
+	sort.SliceStable(floors, func(i, j int) bool {
+		return floors[i].Floor < floors[j].Floor
+	})
+	return floors
+}
+
+// Spread returns the difference between the highest and the lowest marketplace floor in percent of the lowest. It
+// returns false if there are less than two marketplaces.
+func Spread(floors []MarketplaceFloor) (float64, bool) {
+	if len(floors) < 2 {
+		return 0, false
+	}
+	lowest, highest := floors[0].Floor, floors[0].Floor
+	for _, f := range floors[1:] {
+		lowest = min(lowest, f.Floor)
+		highest = max(highest, f.Floor)
+	}
+	return util.PercentChange(lowest, highest), true
+}
+
+// merge merges sorted points b into sorted points a, points of b at the same time as a point of a are dropped.
+func merge(a, b []Point) []Point {
+	result := make([]Point, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i].Time.Before(b[j].Time)):
+			result = append(result, a[i])
+			i++
+		case i < len(a) && a[i].Time.Equal(b[j].Time):
+			j++
+		default:
+			result = append(result, b[j])
+			j++
+		}
+	}
+	return result
+}
+
+func withType(event Event, t EventType) Event {
+	event.Type = t
+	return event
+}
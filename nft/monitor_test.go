@@ -0,0 +1,112 @@
+package nft
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+func drain(m *Monitor) []EventType {
+	var types []EventType
+	for {
+		select {
+		case event := <-m.events:
+			types = append(types, event.Type)
+		default:
+			return types
+		}
+	}
+}
+
+func TestMonitor_Observe(t *testing.T) {
+	m, err := New(coingecko.NewCoinGecko("", false, nil), Config{
+		Collections:   []string{"pudgy-penguins"},
+		MovePercent:   10,
+		SpreadPercent: 5,
+		MaxHistory:    3,
+	})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	now := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	spread := []MarketplaceFloor{{MarketplaceID: "opensea", Floor: 5}, {MarketplaceID: "blur", Floor: 5.5}}
+	narrow := []MarketplaceFloor{{MarketplaceID: "opensea", Floor: 5}, {MarketplaceID: "blur", Floor: 5.1}}
+
+	cases := []struct {
+		name         string
+		native       float64
+		marketplaces []MarketplaceFloor
+		wantedTypes  []EventType
+	}{
+		{name: "first floor", native: 5, wantedTypes: []EventType{EventFloor}},
+		{name: "small move", native: 5.4, wantedTypes: []EventType{EventFloor}},
+		{name: "move", native: 5.6, wantedTypes: []EventType{EventFloor, EventMove}},
+		{name: "spread", native: 5.5, marketplaces: spread, wantedTypes: []EventType{EventFloor, EventSpread}},
+		{name: "spread kept", native: 5.51, marketplaces: spread, wantedTypes: []EventType{EventFloor}},
+		{name: "spread narrowed", native: 5.52, marketplaces: narrow, wantedTypes: []EventType{EventFloor}},
+		{name: "spread again", native: 5.53, marketplaces: spread, wantedTypes: []EventType{EventFloor, EventSpread}},
+	}
+	for i, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			floor := Point{Time: now.Add(time.Duration(i) * time.Minute), Native: tt.native, USD: tt.native * 2000}
+			m.observe(context.TODO(), "pudgy-penguins", "ethereum", floor, tt.marketplaces)
+			if types := drain(m); !reflect.DeepEqual(types, tt.wantedTypes) {
+				t.Fatalf("incorrect events, wanted: %v, got: %v", tt.wantedTypes, types)
+			}
+		})
+	}
+	if history := m.History("pudgy-penguins"); len(history) != 3 || history[0].Native != 5.51 {
+		t.Fatalf("incorrect history, got: %+v", history)
+	}
+}
+
+func TestMonitor_Run(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/market_chart"):
+			_, _ = w.Write([]byte(`{"floor_price_usd":[[1698710400000,9000],[1698796800000,10000]],
+"floor_price_native":[[1698710400000,5],[1698796800000,5.1]]}`))
+		case strings.HasSuffix(r.URL.Path, "/tickers"):
+			_, _ = w.Write([]byte(`{"tickers":[{"floor_price_in_native_currency":5.3,"nft_marketplace_id":"blur",
+"updated_at":"2023-11-01T00:00:00.000Z"},{"floor_price_in_native_currency":5.2,"nft_marketplace_id":"opensea"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"pudgy-penguins","native_currency":"ethereum",
+"floor_price":{"native_currency":5.2,"usd":10400}}`))
+		}
+	}))
+	defer server.Close()
+	client := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	m, err := New(client, Config{Collections: []string{"pudgy-penguins"}, Marketplaces: true, BackfillDays: "14"})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	m.now = func() time.Time { return time.Date(2023, 11, 2, 0, 0, 0, 0, time.UTC) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	event := <-m.Events()
+	if event.Type != EventFloor || event.Floor.USD != 10400 || event.NativeCurrency != "ethereum" {
+		t.Fatalf("incorrect event, got: %+v", event)
+	}
+	if len(event.Marketplaces) != 2 || event.Marketplaces[0].MarketplaceID != "opensea" ||
+		event.Marketplaces[0].UpdatedAt != nil ||
+		!event.Marketplaces[1].UpdatedAt.Equal(time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("incorrect marketplaces, got: %+v", event.Marketplaces)
+	}
+	cancel()
+	<-done
+
+	history := m.History("pudgy-penguins")
+	if len(history) != 3 || history[0].Native != 5 || history[1].USD != 10000 || history[2].Native != 5.2 {
+		t.Fatalf("incorrect history, got: %+v", history)
+	}
+}
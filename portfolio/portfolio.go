@@ -0,0 +1,277 @@
+// Package portfolio values holdings of coins and tokens with batched CoinGecko price lookups, falling back to
+// GeckoTerminal token prices for tokens CoinGecko doesn't list.
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/bufdata/coingecko-api/bridge"
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/currency"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	// maxIDsPerRequest is the number of coin ids or contract addresses sent in one SimplePrice or SimpleTokenPrice
+	// request.
+	maxIDsPerRequest = 100
+	// maxTokensPerRequest is the number of addresses sent in one GetMultiTokensOnOneNetwork request.
+	maxTokensPerRequest = 30
+
+	change24hSuffix = "_24h_change"
+)
+
+// Source of a position price.
+const (
+	SourceCoinGecko     = "coingecko"
+	SourceGeckoTerminal = "geckoterminal"
+)
+
+// Position is a holding of Quantity units of either a coin(CoinID) or a token(Platform and Contract), e.g.
+// {CoinID: "bitcoin"} or {Platform: "ethereum", Contract: "0x..."}.
+type Position struct {
+	CoinID   string  `json:"coin_id,omitempty"`
+	Platform string  `json:"platform,omitempty"`
+	Contract string  `json:"contract,omitempty"`
+	Quantity float64 `json:"quantity"`
+}
+
+// PositionValue is the valuation of a position. Prices, Values and Change24h are keyed by vs currency, Change24h is
+// in percent and missing if the source doesn't report it. Source is empty if no price is found.
+type PositionValue struct {
+	Position  Position           `json:"position"`
+	Source    string             `json:"source,omitempty"`
+	Prices    map[string]float64 `json:"prices,omitempty"`
+	Values    map[string]float64 `json:"values,omitempty"`
+	Change24h map[string]float64 `json:"change_24h,omitempty"`
+}
+
+// Valuation is the valuation of all positions. Total and Change24h are keyed by vs currency, Change24h is the 24h
+// change of Total in percent computed from the positions reporting a 24h change.
+type Valuation struct {
+	Positions []PositionValue    `json:"positions"`
+	Total     map[string]float64 `json:"total"`
+	Change24h map[string]float64 `json:"change_24h"`
+}
+
+// Valuer values positions. It is safe for concurrent use.
+type Valuer struct {
+	cg        *coingecko.Client
+	gt        *geckoterminal.Client
+	bridge    *bridge.Bridge
+	converter *currency.Converter
+}
+
+// New creates a Valuer. gt is used to price tokens CoinGecko doesn't list, the fallback is disabled if it is nil.
+func New(cg *coingecko.Client, gt *geckoterminal.Client) *Valuer {
+	v := &Valuer{cg: cg, gt: gt, converter: currency.NewConverter(cg, 0)}
+	if gt != nil {
+		v.bridge = bridge.New(cg, gt)
+	}
+	return v
+}
+
+// Value values positions in vsCurrencies, e.g. usd, eur and btc. Price lookups are de-duplicated and batched: one
+// SimplePrice call per 100 coin ids and one SimpleTokenPrice call per platform and 100 contract addresses.
+//
+// Failures of the GeckoTerminal fallback are logged and the affected positions are left without price.
+func (v *Valuer) Value(ctx context.Context, positions []Position, vsCurrencies ...string) (*Valuation, error) {
+	if len(vsCurrencies) == 0 {
+		return nil, fmt.Errorf("the length of vsCurrencies should be greater than 0")
+	}
+	lowered := make([]string, 0, len(vsCurrencies))
+	for _, vs := range vsCurrencies {
+		lowered = append(lowered, strings.ToLower(vs))
+	}
+	vsCurrencies = lowered
+
+	var ids []string
+	contracts := make(map[string][]string)
+	for _, p := range positions {
+		switch {
+		case p.CoinID != "":
+			ids = append(ids, p.CoinID)
+		case p.Platform != "" && p.Contract != "":
+			contracts[p.Platform] = append(contracts[p.Platform], util.NormalizeAddress(p.Contract))
+		default:
+			return nil, fmt.Errorf("position should have coin id or platform and contract: %+v", p)
+		}
+	}
+
+	coinPrices, err := v.coinPrices(ctx, unique(ids), vsCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	tokenPrices := make(map[string]map[string]map[string]float64, len(contracts))
+	for platform, addresses := range contracts {
+		if tokenPrices[platform], err = v.tokenPrices(ctx, platform, unique(addresses), vsCurrencies); err != nil {
+			return nil, err
+		}
+	}
+	fallbackPrices := v.fallbackPrices(ctx, contracts, tokenPrices, vsCurrencies)
+
+	valuation := &Valuation{
+		Positions: make([]PositionValue, 0, len(positions)),
+		Total:     make(map[string]float64, len(vsCurrencies)),
+		Change24h: make(map[string]float64, len(vsCurrencies)),
+	}
+	previous := make(map[string]float64, len(vsCurrencies))
+	changed := make(map[string]float64, len(vsCurrencies))
+	for _, p := range positions {
+		value := PositionValue{Position: p}
+		var prices map[string]float64
+		if p.CoinID != "" {
+			prices, value.Source = coinPrices[p.CoinID], SourceCoinGecko
+		} else if prices = tokenPrices[p.Platform][util.NormalizeAddress(p.Contract)]; len(prices) != 0 {
+			value.Source = SourceCoinGecko
+		} else {
+			prices, value.Source = fallbackPrices[p.Platform][util.NormalizeAddress(p.Contract)], SourceGeckoTerminal
+		}
+		if len(prices) == 0 {
+			value.Source = ""
+			valuation.Positions = append(valuation.Positions, value)
+			continue
+		}
+
+		value.Prices = make(map[string]float64, len(vsCurrencies))
+		value.Values = make(map[string]float64, len(vsCurrencies))
+		for _, vs := range vsCurrencies {
+			price, ok := prices[vs]
+			if !ok {
+				continue
+			}
+			value.Prices[vs] = price
+			value.Values[vs] = price * p.Quantity
+			valuation.Total[vs] += value.Values[vs]
+
+			change, ok := prices[vs+change24hSuffix]
+			if !ok || change <= -100 {
+				continue
+			}
+			if value.Change24h == nil {
+				value.Change24h = make(map[string]float64, len(vsCurrencies))
+			}
+			value.Change24h[vs] = change
+			changed[vs] += value.Values[vs]
+			previous[vs] += value.Values[vs] / (1 + change/100)
+		}
+		valuation.Positions = append(valuation.Positions, value)
+	}
+	for vs, prev := range previous {
+		if prev != 0 {
+			valuation.Change24h[vs] = (changed[vs] - prev) / prev * 100
+		}
+	}
+	return valuation, nil
+}
+
+// coinPrices maps coin ids into their prices returned by SimplePrice API.
+func (v *Valuer) coinPrices(ctx context.Context, ids, vsCurrencies []string) (map[string]map[string]float64, error) {
+	result := make(map[string]map[string]float64, len(ids))
+	for _, chunk := range chunks(ids, maxIDsPerRequest) {
+		data, err := v.cg.SimplePrice(ctx, chunk, vsCurrencies, "", "", "true", "", "")
+		if err != nil {
+			return nil, err
+		}
+		for id, prices := range *data {
+			result[id] = prices
+		}
+	}
+	return result, nil
+}
+
+// tokenPrices maps normalized contract addresses into their prices returned by SimpleTokenPrice API.
+func (v *Valuer) tokenPrices(ctx context.Context, platform string, addresses, vsCurrencies []string) (
+	map[string]map[string]float64, error) {
+	result := make(map[string]map[string]float64, len(addresses))
+	for _, chunk := range chunks(addresses, maxIDsPerRequest) {
+		data, err := v.cg.SimpleTokenPrice(ctx, platform, chunk, vsCurrencies, "", "", "true", "", "")
+		if err != nil {
+			return nil, err
+		}
+		for address, prices := range *data {
+			result[util.NormalizeAddress(address)] = prices
+		}
+	}
+	return result, nil
+}
+
+// fallbackPrices prices the contracts missing in tokenPrices with GeckoTerminal USD prices converted into
+// vsCurrencies.
+func (v *Valuer) fallbackPrices(ctx context.Context, contracts map[string][]string,
+	tokenPrices map[string]map[string]map[string]float64, vsCurrencies []string) map[string]map[string]map[string]float64 {
+	result := make(map[string]map[string]map[string]float64)
+	if v.gt == nil {
+		return result
+	}
+
+	for platform, addresses := range contracts {
+		var missing []string
+		for _, address := range unique(addresses) {
+			if len(tokenPrices[platform][address]) == 0 {
+				missing = append(missing, address)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		network, err := v.bridge.Network(ctx, platform)
+		if err != nil {
+			slog.Error("failed to get geckoterminal network", "error", err)
+			continue
+		}
+		result[platform] = make(map[string]map[string]float64, len(missing))
+		for _, chunk := range chunks(missing, maxTokensPerRequest) {
+			data, err := v.gt.GetMultiTokensOnOneNetwork(ctx, network, chunk, nil)
+			if err != nil {
+				slog.Error("failed to get geckoterminal token prices", "error", err)
+				continue
+			}
+			for _, token := range data.Data {
+				price, err := token.Attributes.PriceUSDDecimal()
+				if err != nil || price == nil {
+					continue
+				}
+				prices, err := v.converter.ConvertPrices(ctx, map[string]float64{"usd": price.Float64()}, vsCurrencies...)
+				if err != nil {
+					slog.Error("failed to convert geckoterminal token price", "error", err)
+					continue
+				}
+				result[platform][util.NormalizeAddress(token.Attributes.Address)] = prices
+			}
+		}
+	}
+	return result
+}
+
+// unique returns the sorted distinct values of s.
+func unique(s []string) []string {
+	seen := make(map[string]bool, len(s))
+	result := make([]string, 0, len(s))
+	for _, item := range s {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func chunks(s []string, size int) [][]string {
+	var result [][]string
+	for len(s) > size {
+		result = append(result, s[:size])
+		s = s[size:]
+	}
+	if len(s) != 0 {
+		result = append(result, s)
+	}
+	return result
+}
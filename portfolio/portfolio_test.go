@@ -0,0 +1,92 @@
+package portfolio
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+const (
+	simplePriceResp      = `{"bitcoin":{"usd":40000,"usd_24h_change":10,"eur":36000,"eur_24h_change":10}}`
+	simpleTokenPriceResp = `{"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48":{"usd":1,"usd_24h_change":0,"eur":0.9,"eur_24h_change":0}}`
+	networksResp         = `{"data":[{"id":"eth","type":"network","attributes":{"name":"Ethereum","coingecko_asset_platform_id":"ethereum"}}],"links":{"next":null}}`
+	multiTokensResp      = `{"data":[{"id":"eth_0xnew","type":"token","attributes":{"address":"0xnew","price_usd":"2.5"}}]}`
+	exchangeRatesResp    = `{"rates":{"btc":{"value":1},"usd":{"value":40000},"eur":{"value":36000}}}`
+)
+
+func setup(t *testing.T, paths map[string]int) *Valuer {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths[r.URL.Path]++
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/simple/price"):
+			_, _ = w.Write([]byte(simplePriceResp))
+		case strings.HasSuffix(r.URL.Path, "/simple/token_price/ethereum"):
+			_, _ = w.Write([]byte(simpleTokenPriceResp))
+		case strings.HasSuffix(r.URL.Path, "/networks"):
+			_, _ = w.Write([]byte(networksResp))
+		case strings.Contains(r.URL.Path, "/tokens/multi/"):
+			_, _ = w.Write([]byte(multiTokensResp))
+		case strings.HasSuffix(r.URL.Path, "/exchange_rates"):
+			_, _ = w.Write([]byte(exchangeRatesResp))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cg := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithBaseURL(server.URL+"/api/v2"))
+	return New(cg, gt)
+}
+
+func TestValuer_Value(t *testing.T) {
+	paths := make(map[string]int)
+	v := setup(t, paths)
+	positions := []Position{
+		{CoinID: "bitcoin", Quantity: 0.5},
+		{CoinID: "bitcoin", Quantity: 0.5},
+		{Platform: "ethereum", Contract: "0xA0b86991c6218b36c1d19d4a2e9eb0ce3606eB48", Quantity: 1000},
+		{Platform: "ethereum", Contract: "0xnew", Quantity: 100},
+	}
+	valuation, err := v.Value(context.TODO(), positions, "USD", "eur")
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+
+	if paths["/api/v3/simple/price"] != 1 || paths["/api/v3/simple/token_price/ethereum"] != 1 {
+		t.Fatalf("price lookups should be batched, got: %v", paths)
+	}
+	sources := []string{SourceCoinGecko, SourceCoinGecko, SourceCoinGecko, SourceGeckoTerminal}
+	for i, value := range valuation.Positions {
+		if value.Source != sources[i] {
+			t.Fatalf("incorrect source of position %d, wanted: %s, got: %s", i, sources[i], value.Source)
+		}
+	}
+	if eur := valuation.Positions[3].Values["eur"]; math.Abs(eur-225) > 1e-9 {
+		t.Fatalf("incorrect fallback value, wanted: 225, got: %v", eur)
+	}
+	if total := valuation.Total["usd"]; math.Abs(total-41250) > 1e-9 {
+		t.Fatalf("incorrect total, wanted: 41250, got: %v", total)
+	}
+	// positions reporting 24h change are worth 41000 now and 37363.64 24h ago.
+	if change := valuation.Change24h["usd"]; math.Abs(change-9.7323601) > 1e-6 {
+		t.Fatalf("incorrect 24h change, got: %v", change)
+	}
+}
+
+func TestValuer_Value_InvalidPosition(t *testing.T) {
+	v := setup(t, make(map[string]int))
+	if _, err := v.Value(context.TODO(), []Position{{Platform: "ethereum", Quantity: 1}}, "usd"); err == nil {
+		t.Fatal("error should not be nil")
+	}
+	if _, err := v.Value(context.TODO(), []Position{{CoinID: "bitcoin", Quantity: 1}}); err == nil {
+		t.Fatal("error should not be nil")
+	}
+}
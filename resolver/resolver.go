@@ -0,0 +1,343 @@
+// Package resolver maps user input such as symbols and contract addresses into CoinGecko coin IDs.
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	defaultVsCurrency  = "usd"
+	defaultMarketPages = 4
+	marketsPerPage     = 250
+)
+
+// Coin describes a coin known by Resolver. MarketCap and MarketCapRank are 0 if the coin is not in the ranked
+// market pages.
+type Coin struct {
+	ID            string            `json:"id"`
+	Symbol        string            `json:"symbol"`
+	Name          string            `json:"name"`
+	MarketCap     float64           `json:"market_cap,omitempty"`
+	MarketCapRank int               `json:"market_cap_rank,omitempty"`
+	Platforms     map[string]string `json:"platforms,omitempty"`
+}
+
+// Snapshot is the persisted state of Resolver.
+type Snapshot struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Coins     []Coin    `json:"coins"`
+}
+
+// Options configures Resolver.
+type Options struct {
+	// VsCurrency is the currency used to rank coins by market cap. Default value: usd.
+	VsCurrency string
+	// MarketPages is the number of pages of 250 coins fetched from ListCoinsMarketsData API to rank coins.
+	// Default value: 4.
+	MarketPages uint
+	// RefreshInterval is the interval between refreshes in Run, a snapshot older than it is refreshed in Load.
+	// Zero disables both.
+	RefreshInterval time.Duration
+	// SnapshotPath is the file where the snapshot is persisted after every refresh and loaded from on startup.
+	// Empty disables persistence.
+	SnapshotPath string
+}
+
+// Resolver resolves symbols and contract addresses into CoinGecko coin IDs. It is built on ListCoinsInfo API with
+// platforms included and ListCoinsMarketsData API, and is safe for concurrent use.
+type Resolver struct {
+	client *coingecko.Client
+	opts   Options
+
+	mu         sync.RWMutex
+	snapshot   Snapshot
+	byID       map[string]int
+	bySymbol   map[string][]int
+	byContract map[contractKey]int
+}
+
+type contractKey struct {
+	platform string
+	address  string
+}
+
+// New creates a Resolver. It is empty until Load or Refresh is called.
+func New(client *coingecko.Client, opts Options) *Resolver {
+	if opts.VsCurrency == "" {
+		opts.VsCurrency = defaultVsCurrency
+	}
+	if opts.MarketPages == 0 {
+		opts.MarketPages = defaultMarketPages
+	}
+	r := &Resolver{client: client, opts: opts}
+	r.setSnapshot(Snapshot{})
+	return r
+}
+
+// Load loads the snapshot from SnapshotPath. If there is no snapshot or it is older than RefreshInterval,
+// the snapshot is refreshed from the API.
+func (r *Resolver) Load(ctx context.Context) error {
+	if r.opts.SnapshotPath != "" {
+		snapshot, err := readSnapshot(r.opts.SnapshotPath)
+		switch {
+		case err == nil:
+			r.setSnapshot(snapshot)
+			if r.opts.RefreshInterval == 0 || time.Since(snapshot.UpdatedAt) < r.opts.RefreshInterval {
+				return nil
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			slog.Error("failed to read resolver snapshot", "error", err)
+		}
+	}
+	return r.Refresh(ctx)
+}
+
+// Refresh fetches the coin list and market pages from the API, and persists the snapshot if SnapshotPath is set.
+func (r *Resolver) Refresh(ctx context.Context) error {
+	list, err := r.client.ListCoinsInfo(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	markets := make(map[string]coingecko.ListCoinsMarketsDataResponse)
+	for page := uint(1); page <= r.opts.MarketPages; page++ {
+		data, err := r.client.ListCoinsMarketsData(ctx, r.opts.VsCurrency, nil, "", "market_cap_desc", marketsPerPage,
+			page, false, nil, "", "")
+		if err != nil {
+			return err
+		}
+		for _, item := range *data {
+			markets[item.ID] = item
+		}
+		if len(*data) < marketsPerPage {
+			break
+		}
+	}
+
+	snapshot := Snapshot{UpdatedAt: time.Now().UTC(), Coins: make([]Coin, 0, len(*list))}
+	for _, item := range *list {
+		coin := Coin{ID: item.ID, Symbol: item.Symbol, Name: item.Name}
+		if item.Platforms != nil {
+			coin.Platforms = make(map[string]string, len(*item.Platforms))
+			for platform, address := range *item.Platforms {
+				if platform != "" && address != "" {
+					coin.Platforms[platform] = address
+				}
+			}
+		}
+		if market, ok := markets[item.ID]; ok {
+			coin.MarketCap = market.MarketCap
+			coin.MarketCapRank = market.MarketCapRank
+		}
+		snapshot.Coins = append(snapshot.Coins, coin)
+	}
+	r.setSnapshot(snapshot)
+
+	if r.opts.SnapshotPath != "" {
+		if err = writeSnapshot(r.opts.SnapshotPath, snapshot); err != nil {
+			slog.Error("failed to write resolver snapshot", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Run refreshes the snapshot every RefreshInterval until ctx is done. Failed refreshes are logged and the previous
+// snapshot is kept.
+func (r *Resolver) Run(ctx context.Context) error {
+	if r.opts.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh interval should be greater than 0")
+	}
+
+	ticker := time.NewTicker(r.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil {
+				slog.Error("failed to refresh resolver", "error", err)
+			}
+		}
+	}
+}
+
+// Snapshot returns the current snapshot.
+func (r *Resolver) Snapshot() Snapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.snapshot
+}
+
+// BySymbol returns the coins with symbol, case-insensitive, ranked by market cap in descending order. Coins without
+// market cap are put last.
+func (r *Resolver) BySymbol(symbol string) []Coin {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	indexes := r.bySymbol[strings.ToLower(symbol)]
+	coins := make([]Coin, 0, len(indexes))
+	for _, i := range indexes {
+		coins = append(coins, r.snapshot.Coins[i])
+	}
+	return coins
+}
+
+// ByContract returns the coin deployed at address on the asset platform, e.g. ethereum. Hex addresses are
+// case-insensitive.
+func (r *Resolver) ByContract(platform, address string) (Coin, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i, ok := r.byContract[contractKey{platform: platform, address: util.NormalizeAddress(address)}]
+	if !ok {
+		return Coin{}, false
+	}
+	return r.snapshot.Coins[i], true
+}
+
+// ByID returns the coin with id.
+func (r *Resolver) ByID(id string) (Coin, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i, ok := r.byID[id]
+	if !ok {
+		return Coin{}, false
+	}
+	return r.snapshot.Coins[i], true
+}
+
+// Platforms returns the asset platforms of the coin with id mapped into contract addresses.
+func (r *Resolver) Platforms(id string) map[string]string {
+	coin, _ := r.ByID(id)
+	return coin.Platforms
+}
+
+// Resolve returns the candidate coins of query, which is a coin id, a contract address on any platform or a symbol.
+// Candidates are ranked by market cap in descending order.
+func (r *Resolver) Resolve(query string) ([]Coin, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query should not be empty")
+	}
+
+	if coin, ok := r.ByID(query); ok {
+		return []Coin{coin}, nil
+	}
+
+	r.mu.RLock()
+	var coins []Coin
+	address := util.NormalizeAddress(query)
+	for key, i := range r.byContract {
+		if key.address == address {
+			coins = append(coins, r.snapshot.Coins[i])
+		}
+	}
+	r.mu.RUnlock()
+	if len(coins) != 0 {
+		sortCoins(coins)
+		return dedupCoins(coins), nil
+	}
+
+	if coins = r.BySymbol(query); len(coins) != 0 {
+		return coins, nil
+	}
+	return nil, fmt.Errorf("no coin found for %q", query)
+}
+
+func (r *Resolver) setSnapshot(snapshot Snapshot) {
+	byID := make(map[string]int, len(snapshot.Coins))
+	bySymbol := make(map[string][]int)
+	byContract := make(map[contractKey]int)
+	for i, coin := range snapshot.Coins {
+		byID[coin.ID] = i
+		symbol := strings.ToLower(coin.Symbol)
+		bySymbol[symbol] = append(bySymbol[symbol], i)
+		for platform, address := range coin.Platforms {
+			byContract[contractKey{platform: platform, address: util.NormalizeAddress(address)}] = i
+		}
+	}
+	for _, indexes := range bySymbol {
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return lessCoin(snapshot.Coins[indexes[i]], snapshot.Coins[indexes[j]])
+		})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshot = snapshot
+	r.byID = byID
+	r.bySymbol = bySymbol
+	r.byContract = byContract
+}
+
+// lessCoin orders coins by market cap in descending order, coins without market cap are ordered by id.
+func lessCoin(a, b Coin) bool {
+	if a.MarketCap != b.MarketCap {
+		return a.MarketCap > b.MarketCap
+	}
+	return a.ID < b.ID
+}
+
+func sortCoins(coins []Coin) {
+	sort.SliceStable(coins, func(i, j int) bool {
+		return lessCoin(coins[i], coins[j])
+	})
+}
+
+// dedupCoins removes adjacent duplicated coins of sorted coins.
+func dedupCoins(coins []Coin) []Coin {
+	result := coins[:0]
+	for i, coin := range coins {
+		if i == 0 || coin.ID != coins[i-1].ID {
+			result = append(result, coin)
+		}
+	}
+	return result
+}
+
+func readSnapshot(path string) (Snapshot, error) {
+	var snapshot Snapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// writeSnapshot writes snapshot into a temporary file and renames it to path, so a crash never leaves a partial
+// snapshot behind.
+func writeSnapshot(path string, snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,133 @@
+package resolver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+const (
+	coinsListResp = `[
+{"id":"ethereum","symbol":"eth","name":"Ethereum","platforms":{}},
+{"id":"weth","symbol":"weth","name":"WETH","platforms":{"ethereum":"0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"}},
+{"id":"ethereum-wormhole","symbol":"eth","name":"Ethereum (Wormhole)","platforms":{"solana":"7vfCXTUXx5WJV5JADk17DUJ4ksgau7utNKj4b963voxs"}},
+{"id":"usd-coin","symbol":"usdc","name":"USDC","platforms":{"ethereum":"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48","solana":"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"}}
+]`
+	coinsMarketsResp = `[
+{"id":"ethereum","symbol":"eth","name":"Ethereum","market_cap":200000000000,"market_cap_rank":2},
+{"id":"usd-coin","symbol":"usdc","name":"USDC","market_cap":25000000000,"market_cap_rank":7},
+{"id":"ethereum-wormhole","symbol":"eth","name":"Ethereum (Wormhole)","market_cap":100000000,"market_cap_rank":400}
+]`
+)
+
+func mockServer(t *testing.T, calls *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/coins/list"):
+			_, _ = w.Write([]byte(coinsListResp))
+		case strings.HasSuffix(r.URL.Path, "/coins/markets"):
+			_, _ = w.Write([]byte(coinsMarketsResp))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func setup(t *testing.T, server *httptest.Server, opts Options) *Resolver {
+	t.Helper()
+	client := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	return New(client, opts)
+}
+
+func coinIDs(coins []Coin) []string {
+	ids := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		ids = append(ids, coin.ID)
+	}
+	return ids
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	var calls int
+	server := mockServer(t, &calls)
+	defer server.Close()
+
+	r := setup(t, server, Options{})
+	if err := r.Refresh(context.TODO()); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		query       string
+		wantedIsErr bool
+		wantedIDs   []string
+	}{
+		{name: "symbol ranked by market cap", query: "ETH", wantedIDs: []string{"ethereum", "ethereum-wormhole"}},
+		{name: "coin id", query: "usd-coin", wantedIDs: []string{"usd-coin"}},
+		{name: "hex address case-insensitive", query: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", wantedIDs: []string{"weth"}},
+		{name: "solana address", query: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", wantedIDs: []string{"usd-coin"}},
+		{name: "unknown", query: "unknown", wantedIsErr: true},
+		{name: "empty", query: " ", wantedIsErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			coins, err := r.Resolve(tt.query)
+			if tt.wantedIsErr {
+				if err == nil {
+					t.Fatal("error should not be nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error should be nil, got: %v", err)
+			}
+			if ids := coinIDs(coins); !reflect.DeepEqual(ids, tt.wantedIDs) {
+				t.Fatalf("incorrect coins, wanted: %v, got: %v", tt.wantedIDs, ids)
+			}
+		})
+	}
+
+	coin, ok := r.ByContract("ethereum", "0xA0b86991c6218b36c1d19d4a2e9eb0ce3606eB48")
+	if !ok || coin.ID != "usd-coin" {
+		t.Fatalf("incorrect coin, got: %+v", coin)
+	}
+	if platforms := r.Platforms("usd-coin"); len(platforms) != 2 {
+		t.Fatalf("incorrect platforms, got: %v", platforms)
+	}
+}
+
+func TestResolver_LoadSnapshot(t *testing.T) {
+	var calls int
+	server := mockServer(t, &calls)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "coins.json")
+	r := setup(t, server, Options{SnapshotPath: path})
+	if err := r.Load(context.TODO()); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("incorrect call number, wanted: 2, got: %d", calls)
+	}
+
+	calls = 0
+	loaded := setup(t, server, Options{SnapshotPath: path})
+	if err := loaded.Load(context.TODO()); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("snapshot should be loaded without calling api, got %d calls", calls)
+	}
+	if ids := coinIDs(loaded.BySymbol("eth")); !reflect.DeepEqual(ids, []string{"ethereum", "ethereum-wormhole"}) {
+		t.Fatalf("incorrect coins, got: %v", ids)
+	}
+}
@@ -0,0 +1,111 @@
+package trending
+
+import (
+	"sort"
+	"time"
+)
+
+// Stint is a continuous period of a coin on a list. Exit is the time of the first snapshot without the coin, it is
+// nil if the coin is still on the list at the last snapshot.
+type Stint struct {
+	CoinID    string     `json:"coin_id"`
+	List      string     `json:"list"`
+	Entry     time.Time  `json:"entry"`
+	Exit      *time.Time `json:"exit,omitempty"`
+	BestRank  int        `json:"best_rank"`
+	Snapshots int        `json:"snapshots"`
+}
+
+// Duration returns the time the coin stayed on the list, until end if the stint is still open.
+func (s Stint) Duration(end time.Time) time.Duration {
+	if s.Exit == nil {
+		return end.Sub(s.Entry)
+	}
+	return s.Exit.Sub(s.Entry)
+}
+
+// RankPoint is the rank of a coin at a snapshot.
+type RankPoint struct {
+	Time time.Time `json:"time"`
+	Rank int       `json:"rank"`
+}
+
+// Stints computes the entry and exit times of the coins on list, ordered by entry time and coin id. Snapshots are
+// the distinct row times of list, so a snapshot with an empty list is not seen.
+func Stints(rows []Row, list string) []Stint {
+	snapshots := snapshotRows(rows, list)
+
+	var stints []Stint
+	open := make(map[string]int)
+	for _, snapshot := range snapshots {
+		present := make(map[string]bool, len(snapshot.rows))
+		for _, row := range snapshot.rows {
+			present[row.CoinID] = true
+			i, ok := open[row.CoinID]
+			if !ok {
+				stints = append(stints, Stint{CoinID: row.CoinID, List: list, Entry: snapshot.time, BestRank: row.Rank})
+				i = len(stints) - 1
+				open[row.CoinID] = i
+			}
+			stints[i].Snapshots++
+			stints[i].BestRank = min(stints[i].BestRank, row.Rank)
+		}
+		for id, i := range open {
+			if !present[id] {
+				exit := snapshot.time
+				stints[i].Exit = &exit
+				delete(open, id)
+			}
+		}
+	}
+
+	sort.SliceStable(stints, func(i, j int) bool {
+		if !stints[i].Entry.Equal(stints[j].Entry) {
+			return stints[i].Entry.Before(stints[j].Entry)
+		}
+		return stints[i].CoinID < stints[j].CoinID
+	})
+	return stints
+}
+
+// RankHistory maps the coin ids on list into their ranks in chronological order.
+func RankHistory(rows []Row, list string) map[string][]RankPoint {
+	history := make(map[string][]RankPoint)
+	for _, snapshot := range snapshotRows(rows, list) {
+		for _, row := range snapshot.rows {
+			history[row.CoinID] = append(history[row.CoinID], RankPoint{Time: snapshot.time, Rank: row.Rank})
+		}
+	}
+	return history
+}
+
+type snapshot struct {
+	time time.Time
+	rows []Row
+}
+
+// snapshotRows groups the rows of list by time in chronological order.
+func snapshotRows(rows []Row, list string) []snapshot {
+	byTime := make(map[int64]*snapshot)
+	for _, row := range rows {
+		if row.List != list {
+			continue
+		}
+		key := row.Time.UnixNano()
+		s, ok := byTime[key]
+		if !ok {
+			s = &snapshot{time: row.Time}
+			byTime[key] = s
+		}
+		s.rows = append(s.rows, row)
+	}
+
+	snapshots := make([]snapshot, 0, len(byTime))
+	for _, s := range byTime {
+		snapshots = append(snapshots, *s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].time.Before(snapshots[j].time)
+	})
+	return snapshots
+}
@@ -0,0 +1,42 @@
+package trending
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStintsAndRankHistory(t *testing.T) {
+	base := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return base.Add(time.Duration(i) * 10 * time.Minute) }
+	rows := []Row{
+		{Time: at(0), List: ListTrending, Rank: 1, CoinID: "pepe"},
+		{Time: at(0), List: ListTrending, Rank: 2, CoinID: "bitcoin"},
+		{Time: at(0), List: ListTopGainers, Rank: 1, CoinID: "doge"},
+		{Time: at(1), List: ListTrending, Rank: 1, CoinID: "bitcoin"},
+		{Time: at(2), List: ListTrending, Rank: 2, CoinID: "pepe"},
+		{Time: at(2), List: ListTrending, Rank: 1, CoinID: "bitcoin"},
+	}
+
+	exit := at(1)
+	wantedStints := []Stint{
+		{CoinID: "bitcoin", List: ListTrending, Entry: at(0), BestRank: 1, Snapshots: 3},
+		{CoinID: "pepe", List: ListTrending, Entry: at(0), Exit: &exit, BestRank: 1, Snapshots: 1},
+		{CoinID: "pepe", List: ListTrending, Entry: at(2), BestRank: 2, Snapshots: 1},
+	}
+	stints := Stints(rows, ListTrending)
+	if !reflect.DeepEqual(stints, wantedStints) {
+		t.Fatalf("incorrect stints, wanted: %+v, got: %+v", wantedStints, stints)
+	}
+	if d := stints[0].Duration(at(3)); d != 30*time.Minute {
+		t.Fatalf("incorrect duration, wanted: 30m, got: %s", d)
+	}
+	if d := stints[1].Duration(at(3)); d != 10*time.Minute {
+		t.Fatalf("incorrect duration, wanted: 10m, got: %s", d)
+	}
+
+	wantedHistory := []RankPoint{{Time: at(0), Rank: 2}, {Time: at(1), Rank: 1}, {Time: at(2), Rank: 1}}
+	if history := RankHistory(rows, ListTrending); !reflect.DeepEqual(history["bitcoin"], wantedHistory) {
+		t.Fatalf("incorrect rank history, wanted: %+v, got: %+v", wantedHistory, history["bitcoin"])
+	}
+}
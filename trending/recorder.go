@@ -0,0 +1,157 @@
+// Package trending records the history of trending coins and top gainers and losers, and analyzes how long coins
+// stay on these lists.
+package trending
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+// DefaultInterval matches the update frequency of SearchTrending API.
+const DefaultInterval = 10 * time.Minute
+
+// Lists recorded by Recorder.
+const (
+	ListTrending   = "trending"
+	ListTopGainers = "top_gainers"
+	ListTopLosers  = "top_losers"
+)
+
+// Row is a coin on a list at the time of a snapshot. Rank starts from 1. Price is in BTC for trending coins and in
+// USD for top gainers and losers, Volume24h is only reported for top gainers and losers.
+type Row struct {
+	Time          time.Time `json:"time"`
+	List          string    `json:"list"`
+	Rank          int       `json:"rank"`
+	CoinID        string    `json:"coin_id"`
+	Symbol        string    `json:"symbol"`
+	Name          string    `json:"name"`
+	MarketCapRank int       `json:"market_cap_rank,omitempty"`
+	Price         float64   `json:"price"`
+	Currency      string    `json:"currency"`
+	Volume24h     float64   `json:"volume_24h,omitempty"`
+}
+
+// Config configures Recorder.
+type Config struct {
+	// Trending enables recording SearchTrending API.
+	Trending bool
+	// GainersLosers enables recording GetTopGainersLosers API, which requires a paid plan.
+	GainersLosers bool
+	// Duration and TopCoins are the query parameters of GetTopGainersLosers API, vs_currency is always usd.
+	Duration string
+	TopCoins string
+	// Interval is the interval between snapshots. Default value: DefaultInterval.
+	Interval time.Duration
+}
+
+// Recorder snapshots the enabled lists on a schedule and writes the rows to a Sink.
+type Recorder struct {
+	client *coingecko.Client
+	sink   Sink
+	cfg    Config
+	now    func() time.Time
+}
+
+// NewRecorder creates a Recorder.
+func NewRecorder(client *coingecko.Client, sink Sink, cfg Config) (*Recorder, error) {
+	if !cfg.Trending && !cfg.GainersLosers {
+		return nil, fmt.Errorf("at least one list should be enabled")
+	}
+	if sink == nil {
+		return nil, fmt.Errorf("sink should not be nil")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	return &Recorder{client: client, sink: sink, cfg: cfg, now: time.Now}, nil
+}
+
+// Snapshot fetches the enabled lists once and writes their rows, all rows share the same time. If a list fails, the
+// rows of the other lists are still written and returned with the joined errors.
+func (r *Recorder) Snapshot(ctx context.Context) ([]Row, error) {
+	now := r.now().UTC()
+	var rows []Row
+	var errs []error
+	if r.cfg.Trending {
+		data, err := r.client.SearchTrending(ctx)
+		if err != nil {
+			slog.Error("failed to get trending list", "error", err)
+			errs = append(errs, fmt.Errorf("failed to get trending list: %w", err))
+		} else {
+			for i, coin := range data.Coins {
+				rows = append(rows, Row{
+					Time:          now,
+					List:          ListTrending,
+					Rank:          i + 1,
+					CoinID:        coin.ID,
+					Symbol:        coin.Symbol,
+					Name:          coin.Name,
+					MarketCapRank: coin.MarketCapRank,
+					Price:         coin.PriceBTC,
+					Currency:      "btc",
+				})
+			}
+		}
+	}
+	if r.cfg.GainersLosers {
+		data, err := r.client.GetTopGainersLosers(ctx, "usd", r.cfg.Duration, r.cfg.TopCoins)
+		if err != nil {
+			slog.Error("failed to get top gainers and losers", "error", err)
+			errs = append(errs, fmt.Errorf("failed to get top gainers and losers: %w", err))
+		} else {
+			rows = append(rows, gainersLosersRows(now, ListTopGainers, data.TopGainers)...)
+			rows = append(rows, gainersLosersRows(now, ListTopLosers, data.TopLosers)...)
+		}
+	}
+
+	if err := r.sink.Write(ctx, rows); err != nil {
+		slog.Error("failed to write trending rows", "error", err)
+		return nil, errors.Join(append(errs, err)...)
+	}
+	return rows, errors.Join(errs...)
+}
+
+// Run takes a snapshot immediately and then every Interval until ctx is done. Snapshot errors are logged and
+// skipped.
+func (r *Recorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Snapshot(ctx); err != nil {
+			slog.Error("failed to snapshot trending lists", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func gainersLosersRows(now time.Time, list string, items []coingecko.TopGainerLosersItem) []Row {
+	rows := make([]Row, 0, len(items))
+	for i, item := range items {
+		row := Row{
+			Time:      now,
+			List:      list,
+			Rank:      i + 1,
+			CoinID:    item.ID,
+			Symbol:    item.Symbol,
+			Name:      item.Name,
+			Price:     item.USD,
+			Currency:  "usd",
+			Volume24h: item.USD24hVol,
+		}
+		if item.MarketCapRank != nil {
+			row.MarketCapRank = int(*item.MarketCapRank)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
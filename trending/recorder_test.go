@@ -0,0 +1,119 @@
+package trending
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+)
+
+const (
+	searchTrendingResp = `{"coins":[
+{"item":{"id":"pepe","name":"Pepe","symbol":"PEPE","market_cap_rank":80,"price_btc":1e-10}},
+{"item":{"id":"bitcoin","name":"Bitcoin","symbol":"BTC","market_cap_rank":1,"price_btc":1}}
+]}`
+	topGainersLosersResp = `{"top_gainers":[{"id":"pepe","symbol":"pepe","name":"Pepe","market_cap_rank":80,"usd":0.000001,"usd_24h_vol":1000}],
+"top_losers":[{"id":"doge","symbol":"doge","name":"Dogecoin","market_cap_rank":null,"usd":0.07,"usd_24h_vol":2000}]}`
+)
+
+func setup(t *testing.T) *coingecko.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/search/trending") {
+			_, _ = w.Write([]byte(searchTrendingResp))
+			return
+		}
+		_, _ = w.Write([]byte(topGainersLosersResp))
+	}))
+	t.Cleanup(server.Close)
+
+	return coingecko.NewCoinGecko("", true, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+}
+
+func TestRecorder_Snapshot(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := NewRecorder(setup(t), NewJSONLSink(&buf), Config{Trending: true, GainersLosers: true})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	now := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	rows, err := r.Snapshot(context.TODO())
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	wanted := []Row{
+		{Time: now, List: ListTrending, Rank: 1, CoinID: "pepe", Symbol: "PEPE", Name: "Pepe", MarketCapRank: 80, Price: 1e-10, Currency: "btc"},
+		{Time: now, List: ListTrending, Rank: 2, CoinID: "bitcoin", Symbol: "BTC", Name: "Bitcoin", MarketCapRank: 1, Price: 1, Currency: "btc"},
+		{Time: now, List: ListTopGainers, Rank: 1, CoinID: "pepe", Symbol: "pepe", Name: "Pepe", MarketCapRank: 80, Price: 0.000001, Currency: "usd", Volume24h: 1000},
+		{Time: now, List: ListTopLosers, Rank: 1, CoinID: "doge", Symbol: "doge", Name: "Dogecoin", Price: 0.07, Currency: "usd", Volume24h: 2000},
+	}
+	if !reflect.DeepEqual(rows, wanted) {
+		t.Fatalf("incorrect rows, wanted: %+v, got: %+v", wanted, rows)
+	}
+
+	read, err := ReadJSONL(&buf)
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if !reflect.DeepEqual(read, wanted) {
+		t.Fatalf("incorrect jsonl rows, wanted: %+v, got: %+v", wanted, read)
+	}
+}
+
+func TestRecorder_SnapshotPartial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/search/trending") {
+			_, _ = w.Write([]byte(searchTrendingResp))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"pro api only"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	r, err := NewRecorder(client, NewJSONLSink(&buf), Config{Trending: true, GainersLosers: true})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	rows, err := r.Snapshot(context.TODO())
+	if err == nil || !strings.Contains(err.Error(), "top gainers and losers") {
+		t.Fatalf("incorrect error, got: %v", err)
+	}
+	if len(rows) != 2 || rows[0].List != ListTrending {
+		t.Fatalf("trending rows should be returned, got: %+v", rows)
+	}
+	read, err := ReadJSONL(&buf)
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	if len(read) != 2 {
+		t.Fatalf("trending rows should be written, got: %+v", read)
+	}
+}
+
+func TestCSVSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewCSVSink(&buf, true)
+	row := Row{Time: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), List: ListTrending, Rank: 1, CoinID: "pepe",
+		Symbol: "PEPE", Name: "Pepe, the frog", Price: 1e-10, Currency: "btc"}
+	for i := 0; i < 2; i++ {
+		if err := sink.Write(context.TODO(), []Row{row}); err != nil {
+			t.Fatalf("error should be nil, got: %v", err)
+		}
+	}
+	record := "2023-11-01T00:00:00Z,trending,1,pepe,PEPE,\"Pepe, the frog\",0,0.0000000001,btc,0\n"
+	wanted := "time,list,rank,coin_id,symbol,name,market_cap_rank,price,currency,volume_24h\n" + record + record
+	if buf.String() != wanted {
+		t.Fatalf("incorrect csv, wanted: %q, got: %q", wanted, buf.String())
+	}
+}
@@ -0,0 +1,110 @@
+package trending
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bufdata/coingecko-api/util"
+)
+
+// Sink writes the rows of a snapshot.
+type Sink interface {
+	Write(ctx context.Context, rows []Row) error
+}
+
+// SinkFunc adapts a function into a Sink.
+type SinkFunc func(ctx context.Context, rows []Row) error
+
+// Write implements Sink.
+func (f SinkFunc) Write(ctx context.Context, rows []Row) error {
+	return f(ctx, rows)
+}
+
+// JSONLSink writes one JSON object per row and line.
+type JSONLSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLSink creates a JSONLSink writing into w, e.g. a file opened in append mode.
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{w: w}
+}
+
+// Write implements Sink.
+func (s *JSONLSink) Write(_ context.Context, rows []Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return util.WriteJSONL(s.w, rows)
+}
+
+// csvHeader is the header written by CSVSink.
+var csvHeader = []string{"time", "list", "rank", "coin_id", "symbol", "name", "market_cap_rank", "price", "currency",
+	"volume_24h"}
+
+// CSVSink writes rows as CSV records with a header before the first record.
+type CSVSink struct {
+	mu     sync.Mutex
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVSink creates a CSVSink writing into w. Set header to false when appending to a file which already has one.
+func NewCSVSink(w io.Writer, header bool) *CSVSink {
+	return &CSVSink{w: csv.NewWriter(w), header: header}
+}
+
+// Write implements Sink.
+func (s *CSVSink) Write(_ context.Context, rows []Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.header {
+		if err := s.w.Write(csvHeader); err != nil {
+			return err
+		}
+		s.header = false
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Time.Format(time.RFC3339),
+			row.List,
+			strconv.Itoa(row.Rank),
+			row.CoinID,
+			row.Symbol,
+			row.Name,
+			strconv.Itoa(row.MarketCapRank),
+			strconv.FormatFloat(row.Price, 'f', -1, 64),
+			row.Currency,
+			strconv.FormatFloat(row.Volume24h, 'f', -1, 64),
+		}
+		if err := s.w.Write(record); err != nil {
+			return err
+		}
+	}
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// ReadJSONL reads rows written by JSONLSink.
+func ReadJSONL(r io.Reader) ([]Row, error) {
+	var rows []Row
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var row Row
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
@@ -0,0 +1,145 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CallInfo describes an API call of a client method.
+type CallInfo struct {
+	// API is the name of the API, coingecko or geckoterminal.
+	API string
+	// Method is the client method which makes the call, e.g. GetCoinDataByCoinID.
+	Method string
+	// PathTemplate is the path of the endpoint with %s for path parameters, e.g. /coins/%s.
+	PathTemplate string
+	// Endpoint is the URL of the call, including the query string.
+	Endpoint string
+}
+
+// Template returns PathTemplate with {} in place of path parameters, e.g. /coins/{}/tickers. It does not vary with coin
+// ids or addresses, so it can be used as a metric label.
+func (c CallInfo) Template() string {
+	return strings.ReplaceAll(c.PathTemplate, "%s", "{}")
+}
+
+// SendFunc sends a GET request to call.Endpoint and returns the response body and header, it is how the API clients
+// send requests.
+type SendFunc func(ctx context.Context, call CallInfo) ([]byte, http.Header, error)
+
+// Middleware wraps a SendFunc, e.g. to instrument or retry requests.
+type Middleware func(next SendFunc) SendFunc
+
+// Chain wraps send with middlewares, the first middleware is the outermost one.
+func Chain(send SendFunc, middlewares ...Middleware) SendFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		send = middlewares[i](send)
+	}
+	return send
+}
+
+// APIError is returned by the API clients when the API responds with a status code other than 200.
+type APIError struct {
+	URL        string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("failed to call %s, status code: %d, error message: %s", e.URL, e.StatusCode, e.Message)
+}
+
+// StatusCode returns the status code of the response which err is returned for: 200 if err is nil, the status code
+// of APIError, or 0 if the request fails without response.
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// RequestTrace is a set of hooks called while a request is sent. Like httptrace.ClientTrace, it is carried by the
+// request context, so that middlewares can observe what happens inside the middlewares and the client they wrap.
+// Any hook may be nil.
+type RequestTrace struct {
+	// RateLimitWait is called after the client waits for its rate limiter.
+	RateLimitWait func(wait time.Duration)
+	// Retry is called before a failed request is retried, attempt is 1 for the first retry.
+	Retry func(attempt int, err error)
+}
+
+type requestTraceKey struct{}
+
+// WithRequestTrace returns a copy of ctx which carries trace. Like httptrace.WithClientTrace, if ctx already carries a
+// RequestTrace, the hooks of trace are called before the hooks of the existing one.
+func WithRequestTrace(ctx context.Context, trace *RequestTrace) context.Context {
+	if old := ContextRequestTrace(ctx); old != nil {
+		hooks := trace
+		trace = &RequestTrace{
+			RateLimitWait: func(wait time.Duration) {
+				if hooks.RateLimitWait != nil {
+					hooks.RateLimitWait(wait)
+				}
+				if old.RateLimitWait != nil {
+					old.RateLimitWait(wait)
+				}
+			},
+			Retry: func(attempt int, err error) {
+				if hooks.Retry != nil {
+					hooks.Retry(attempt, err)
+				}
+				if old.Retry != nil {
+					old.Retry(attempt, err)
+				}
+			},
+		}
+	}
+	return context.WithValue(ctx, requestTraceKey{}, trace)
+}
+
+// ContextRequestTrace returns the RequestTrace of ctx, or nil if there is none.
+func ContextRequestTrace(ctx context.Context) *RequestTrace {
+	trace, _ := ctx.Value(requestTraceKey{}).(*RequestTrace)
+	return trace
+}
+
+// Retry returns a Middleware which retries requests failed with 429 Too Many Requests, a 5xx status code or without
+// response, up to maxRetries times. It waits backoff before the first retry and doubles the wait for each retry.
+func Retry(maxRetries int, backoff time.Duration) Middleware {
+	return func(next SendFunc) SendFunc {
+		return func(ctx context.Context, call CallInfo) ([]byte, http.Header, error) {
+			wait := backoff
+			for attempt := 0; ; attempt++ {
+				data, header, err := next(ctx, call)
+				if err == nil || attempt >= maxRetries || !retryable(err) || ctx.Err() != nil {
+					return data, header, err
+				}
+				if trace := ContextRequestTrace(ctx); trace != nil && trace.Retry != nil {
+					trace.Retry(attempt+1, err)
+				}
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, nil, err
+				case <-timer.C:
+				}
+				wait *= 2
+			}
+		}
+	}
+}
+
+// retryable tells whether a request failed with err may succeed if it is sent again.
+func retryable(err error) bool {
+	code := StatusCode(err)
+	return code == 0 || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	middleware := func(name string) Middleware {
+		return func(next SendFunc) SendFunc {
+			return func(ctx context.Context, call CallInfo) ([]byte, http.Header, error) {
+				calls = append(calls, name)
+				return next(ctx, call)
+			}
+		}
+	}
+	send := func(ctx context.Context, call CallInfo) ([]byte, http.Header, error) {
+		calls = append(calls, "send")
+		return []byte(call.Endpoint), nil, nil
+	}
+	data, _, err := Chain(send, middleware("outer"), middleware("inner"))(context.Background(),
+		CallInfo{Endpoint: "endpoint"})
+	if err != nil || string(data) != "endpoint" {
+		t.Fatalf("incorrect result: %s, error: %v", data, err)
+	}
+	if strings.Join(calls, ",") != "outer,inner,send" {
+		t.Fatalf("incorrect order of calls: %v", calls)
+	}
+}
+
+func TestCallInfo_Template(t *testing.T) {
+	call := CallInfo{PathTemplate: "/onchain/networks/%s/pools/%s/ohlcv/%s"}
+	if result := call.Template(); result != "/onchain/networks/{}/pools/{}/ohlcv/{}" {
+		t.Fatalf("incorrect template: %s", result)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	apiErr := &APIError{URL: "https://api.coingecko.com/api/v3/ping", StatusCode: 429, Message: "rate limited"}
+	cases := []struct {
+		err    error
+		wanted int
+	}{
+		{err: nil, wanted: 200},
+		{err: apiErr, wanted: 429},
+		{err: fmt.Errorf("wrapped: %w", apiErr), wanted: 429},
+		{err: errors.New("connection refused"), wanted: 0},
+	}
+	for _, tt := range cases {
+		if result := StatusCode(tt.err); result != tt.wanted {
+			t.Fatalf("incorrect status code of %v, wanted: %d, got: %d", tt.err, tt.wanted, result)
+		}
+	}
+	wanted := "failed to call https://api.coingecko.com/api/v3/ping, status code: 429, error message: rate limited"
+	if apiErr.Error() != wanted {
+		t.Fatalf("incorrect error message, wanted: %s, got: %s", wanted, apiErr.Error())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		name          string
+		errs          []error
+		wantedCalls   int
+		wantedRetries []int
+		wantedIsErr   bool
+	}{
+		{name: "success", errs: []error{nil}, wantedCalls: 1},
+		{name: "retried", errs: []error{&APIError{StatusCode: 429}, &APIError{StatusCode: 502}, nil}, wantedCalls: 3,
+			wantedRetries: []int{1, 2}},
+		{name: "not retryable", errs: []error{&APIError{StatusCode: 404}}, wantedCalls: 1, wantedIsErr: true},
+		{name: "exhausted", errs: []error{errors.New("timeout"), errors.New("timeout"), errors.New("timeout")},
+			wantedCalls: 3, wantedRetries: []int{1, 2}, wantedIsErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			send := func(ctx context.Context, call CallInfo) ([]byte, http.Header, error) {
+				err := tt.errs[min(calls, len(tt.errs)-1)]
+				calls++
+				return nil, nil, err
+			}
+			var retries []int
+			ctx := WithRequestTrace(context.Background(), &RequestTrace{
+				Retry: func(attempt int, err error) { retries = append(retries, attempt) },
+			})
+			_, _, err := Retry(2, time.Millisecond)(send)(ctx, CallInfo{Endpoint: "endpoint"})
+			if (err != nil) != tt.wantedIsErr {
+				t.Fatalf("incorrect error: %v", err)
+			}
+			if calls != tt.wantedCalls {
+				t.Fatalf("incorrect calls, wanted: %d, got: %d", tt.wantedCalls, calls)
+			}
+			if fmt.Sprint(retries) != fmt.Sprint(tt.wantedRetries) {
+				t.Fatalf("incorrect retries, wanted: %v, got: %v", tt.wantedRetries, retries)
+			}
+		})
+	}
+}
+
+func TestWithRequestTrace(t *testing.T) {
+	var calls []string
+	ctx := WithRequestTrace(context.Background(), &RequestTrace{
+		RateLimitWait: func(time.Duration) { calls = append(calls, "outer wait") },
+	})
+	ctx = WithRequestTrace(ctx, &RequestTrace{
+		RateLimitWait: func(time.Duration) { calls = append(calls, "inner wait") },
+		Retry:         func(int, error) { calls = append(calls, "inner retry") },
+	})
+	trace := ContextRequestTrace(ctx)
+	trace.RateLimitWait(time.Second)
+	trace.Retry(1, nil)
+	if strings.Join(calls, ",") != "inner wait,outer wait,inner retry" {
+		t.Fatalf("incorrect calls: %v", calls)
+	}
+	if ContextRequestTrace(context.Background()) != nil {
+		t.Fatal("trace should be nil")
+	}
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits calls to a fixed number per minute by spacing them evenly.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter returns a RateLimiter which allows callsPerMinute calls per minute.
+func NewRateLimiter(callsPerMinute int) *RateLimiter {
+	if callsPerMinute <= 0 {
+		callsPerMinute = 1
+	}
+	return &RateLimiter{interval: time.Minute / time.Duration(callsPerMinute)}
+}
+
+// Wait blocks until the next call is allowed or ctx is done, it returns how long it has waited. The reserved call is
+// given back if ctx is done and no later call has been reserved.
+func (r *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	r.mu.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	wait := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	reserved := r.next
+	r.mu.Unlock()
+
+	if wait <= 0 {
+		return 0, nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		r.mu.Lock()
+		if r.next.Equal(reserved) {
+			r.next = r.next.Add(-r.interval)
+		}
+		r.mu.Unlock()
+		return time.Since(now), ctx.Err()
+	case <-timer.C:
+		return wait, nil
+	}
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := NewRateLimiter(600)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("error should be nil, got: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("calls should be spaced by 100ms, elapsed: %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.Wait(ctx); err == nil {
+		t.Fatal("error should not be nil when context is canceled")
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	limiter := NewRateLimiter(60)
+	if _, err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	// the canceled calls give their reservations back, so the next call waits about one interval.
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		if _, err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("error should be deadline exceeded, got: %v", err)
+		}
+		cancel()
+	}
+
+	limiter.mu.Lock()
+	wait := time.Until(limiter.next)
+	limiter.mu.Unlock()
+	if wait > time.Second {
+		t.Fatalf("canceled calls should not be reserved, next call waits: %v", wait)
+	}
+}
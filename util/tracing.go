@@ -0,0 +1,56 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// CallResult describes how an API call ends.
+type CallResult struct {
+	// StatusCode is the status code of the last response, 0 if the call failed without response.
+	StatusCode int
+	// Retries is the number of retries, see Retry.
+	Retries int
+	// CacheHit tells whether the response is served from a cache, e.g. cgproxy or the CDN of the API.
+	CacheHit bool
+	// ResponseSize is the size of the response body in bytes.
+	ResponseSize int
+	Err          error
+}
+
+// Tracer traces the API calls of the clients, e.g. as spans of distributed traces. The cgotel module adapts
+// OpenTelemetry tracers.
+type Tracer interface {
+	// StartCall is called before a call is sent. The call is sent with the returned context, so that it can carry the
+	// span, and the returned CallSpan is ended once the call returns.
+	StartCall(ctx context.Context, call CallInfo) (context.Context, CallSpan)
+}
+
+// CallSpan is an API call in progress.
+type CallSpan interface {
+	End(result CallResult)
+}
+
+// TraceCall sends call with send and traces it with tracer.
+func TraceCall(ctx context.Context, tracer Tracer, call CallInfo, send SendFunc) ([]byte, http.Header, error) {
+	ctx, span := tracer.StartCall(ctx, call)
+	retries := 0
+	ctx = WithRequestTrace(ctx, &RequestTrace{Retry: func(int, error) { retries++ }})
+
+	data, header, err := send(ctx, call)
+	span.End(CallResult{
+		StatusCode:   StatusCode(err),
+		Retries:      retries,
+		CacheHit:     cacheHit(header),
+		ResponseSize: len(data),
+		Err:          err,
+	})
+	return data, header, err
+}
+
+// cacheHit tells whether a response is served from a cache: cgproxy sets X-Cache, and Cloudflare, which the APIs are
+// behind, sets CF-Cache-Status.
+func cacheHit(header http.Header) bool {
+	return strings.EqualFold(header.Get("X-Cache"), "HIT") || strings.EqualFold(header.Get("CF-Cache-Status"), "HIT")
+}
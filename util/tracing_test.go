@@ -0,0 +1,78 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type recordingTracer struct {
+	calls   []CallInfo
+	results []CallResult
+}
+
+func (r *recordingTracer) StartCall(ctx context.Context, call CallInfo) (context.Context, CallSpan) {
+	r.calls = append(r.calls, call)
+	return ctx, r
+}
+
+func (r *recordingTracer) End(result CallResult) {
+	r.results = append(r.results, result)
+}
+
+func TestTraceCall(t *testing.T) {
+	call := CallInfo{API: "coingecko", Method: "Ping", PathTemplate: "/ping",
+		Endpoint: "https://api.coingecko.com/api/v3/ping"}
+	cases := []struct {
+		name         string
+		errs         []error
+		header       http.Header
+		wantedResult CallResult
+	}{
+		{
+			name:         "cache hit",
+			errs:         []error{nil},
+			header:       http.Header{"X-Cache": []string{"HIT"}},
+			wantedResult: CallResult{StatusCode: 200, CacheHit: true, ResponseSize: 4},
+		},
+		{
+			name:         "retried",
+			errs:         []error{&APIError{StatusCode: 503}, nil},
+			header:       http.Header{"Cf-Cache-Status": []string{"MISS"}},
+			wantedResult: CallResult{StatusCode: 200, Retries: 1, ResponseSize: 4},
+		},
+		{
+			name:         "failed",
+			errs:         []error{&APIError{StatusCode: 404}},
+			wantedResult: CallResult{StatusCode: 404},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			attempt := 0
+			send := func(ctx context.Context, call CallInfo) ([]byte, http.Header, error) {
+				err := tt.errs[attempt]
+				attempt++
+				if err != nil {
+					return nil, nil, err
+				}
+				return []byte("pong"), tt.header, nil
+			}
+			tracer := &recordingTracer{}
+			_, _, err := TraceCall(context.Background(), tracer, call, Retry(1, time.Millisecond)(send))
+			if len(tracer.calls) != 1 || tracer.calls[0] != call || len(tracer.results) != 1 {
+				t.Fatalf("incorrect calls: %v, results: %v", tracer.calls, tracer.results)
+			}
+			result := tracer.results[0]
+			if !errors.Is(result.Err, err) {
+				t.Fatalf("incorrect error, wanted: %v, got: %v", err, result.Err)
+			}
+			result.Err = nil
+			if result != tt.wantedResult {
+				t.Fatalf("incorrect result, wanted: %+v, got: %+v", tt.wantedResult, result)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package util
 
 import (
+	"encoding/json"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // GetLogger returns default customized log.
@@ -25,3 +28,31 @@ func GetLogger(name string) {
 func CalculateTotalPages(totalCount, pageSize int) int {
 	return (totalCount + pageSize - 1) / pageSize
 }
+
+// NormalizeAddress lowercases hex addresses, other addresses(e.g. solana) are case-sensitive.
+func NormalizeAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+// PercentChange returns the change from from to to in percent, 0 if from is 0.
+func PercentChange(from, to float64) float64 {
+	if from == 0 {
+		return 0
+	}
+	return (to - from) / from * 100
+}
+
+// WriteJSONL writes one JSON object per row and line into w.
+func WriteJSONL[T any](w io.Writer, rows []T) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
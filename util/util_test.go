@@ -1,6 +1,7 @@
 package util
 
 import (
+	"bytes"
 	"log/slog"
 	"testing"
 )
@@ -16,3 +17,38 @@ func TestCalculateTotalPages(t *testing.T) {
 		t.Fatalf("incorrect total page number, wanted result: 11, got result: %d", result)
 	}
 }
+
+func TestNormalizeAddress(t *testing.T) {
+	cases := map[string]string{
+		" 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		"0XABC": "0xabc",
+		"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v": "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+	}
+	for address, wanted := range cases {
+		if result := NormalizeAddress(address); result != wanted {
+			t.Fatalf("incorrect address of %q, wanted: %s, got: %s", address, wanted, result)
+		}
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	if result := PercentChange(200, 150); result != -25 {
+		t.Fatalf("incorrect change, wanted: -25, got: %v", result)
+	}
+	if result := PercentChange(0, 150); result != 0 {
+		t.Fatalf("change from 0 should be 0, got: %v", result)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	rows := []struct {
+		ID string `json:"id"`
+	}{{ID: "bitcoin"}, {ID: "ethereum"}}
+	if err := WriteJSONL(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	if wanted := "{\"id\":\"bitcoin\"}\n{\"id\":\"ethereum\"}\n"; buf.String() != wanted {
+		t.Fatalf("incorrect output, wanted: %q, got: %q", wanted, buf.String())
+	}
+}
@@ -0,0 +1,322 @@
+// Package watch polls coin and pool prices and emits typed events for price updates, moves, threshold crossings
+// and stale data.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+	"github.com/bufdata/coingecko-api/util"
+)
+
+const (
+	// DefaultCoinInterval matches the update frequency of SimplePrice API for the public plan.
+	DefaultCoinInterval = 60 * time.Second
+	// DefaultPoolInterval matches the update frequency of GeckoTerminal pool data.
+	DefaultPoolInterval = 60 * time.Second
+
+	defaultVsCurrency = "usd"
+	defaultBuffer     = 64
+
+	// maxCoinsPerRequest is the number of coin ids sent in one SimplePrice request.
+	maxCoinsPerRequest = 100
+	// maxPoolsPerRequest is the number of pool addresses accepted by GetMultiPools API.
+	maxPoolsPerRequest = 30
+)
+
+// EventType is the type of Event.
+type EventType int
+
+const (
+	// EventPrice is emitted for every new price of a target.
+	EventPrice EventType = iota
+	// EventMove is emitted when the price moved by at least Config.MovePercent since the last move event(or the first
+	// price).
+	EventMove
+	// EventThreshold is emitted when the price crosses one of the thresholds of the target.
+	EventThreshold
+	// EventStale is emitted once when the last_updated_at of a coin price becomes older than Config.StaleAfter.
+	EventStale
+	// EventError is emitted when a poll fails, the watcher keeps polling.
+	EventError
+)
+
+// String returns the name of t.
+func (t EventType) String() string {
+	switch t {
+	case EventPrice:
+		return "price"
+	case EventMove:
+		return "move"
+	case EventThreshold:
+		return "threshold"
+	case EventStale:
+		return "stale"
+	case EventError:
+		return "error"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+}
+
+// Target is a watched coin(CoinID) or pool(Network and PoolAddress).
+type Target struct {
+	CoinID      string `json:"coin_id,omitempty"`
+	Network     string `json:"network,omitempty"`
+	PoolAddress string `json:"pool_address,omitempty"`
+}
+
+// Key returns the coin id of a coin, or network_address of a pool like GeckoTerminal pool ids. Hex addresses are
+// lowercased, other addresses(e.g. solana) are case-sensitive.
+func (t Target) Key() string {
+	if t.CoinID != "" {
+		return t.CoinID
+	}
+	return t.Network + "_" + util.NormalizeAddress(t.PoolAddress)
+}
+
+// Event is emitted by Watcher. Price and PrevPrice are in Config.VsCurrency for coins and in USD(base token price)
+// for pools.
+type Event struct {
+	Type   EventType `json:"type"`
+	Target Target    `json:"target"`
+	Time   time.Time `json:"time"`
+	Price  float64   `json:"price,omitempty"`
+	// PrevPrice is the previous price for EventPrice and EventThreshold, and the reference price for EventMove.
+	PrevPrice float64 `json:"prev_price,omitempty"`
+	// ChangePercent is the change from PrevPrice in percent.
+	ChangePercent float64 `json:"change_percent,omitempty"`
+	// Threshold is the crossed threshold of EventThreshold.
+	Threshold float64 `json:"threshold,omitempty"`
+	// UpdatedAt is the last_updated_at of coin prices, it is nil for pools.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Err       error      `json:"-"`
+}
+
+// Config configures Watcher.
+type Config struct {
+	// Coins are the watched coin ids.
+	Coins []string
+	// Pools are the watched pools, CoinID of them is ignored.
+	Pools []Target
+	// VsCurrency of coin prices. Default value: usd.
+	VsCurrency string
+	// CoinInterval is the interval between coin polls. Default value: DefaultCoinInterval.
+	CoinInterval time.Duration
+	// PoolInterval is the interval between pool polls. Default value: DefaultPoolInterval.
+	PoolInterval time.Duration
+	// MovePercent enables EventMove if it is greater than 0.
+	MovePercent float64
+	// Thresholds maps Target.Key into price thresholds.
+	Thresholds map[string][]float64
+	// StaleAfter enables EventStale if it is greater than 0. Pools report no last_updated_at and never go stale.
+	StaleAfter time.Duration
+	// Buffer is the buffer size of the event channel. Default value: 64.
+	Buffer int
+}
+
+// Watcher polls prices and emits events. All coins share one SimplePrice request per cycle, pools share one
+// GetMultiPools request per network per cycle.
+type Watcher struct {
+	cg     *coingecko.Client
+	gt     *geckoterminal.Client
+	cfg    Config
+	events chan Event
+	states map[string]*state
+	now    func() time.Time
+}
+
+type state struct {
+	price float64
+	ref   float64
+	stale bool
+}
+
+// New creates a Watcher. gt may be nil if no pool is watched.
+func New(cg *coingecko.Client, gt *geckoterminal.Client, cfg Config) (*Watcher, error) {
+	if len(cfg.Coins) == 0 && len(cfg.Pools) == 0 {
+		return nil, fmt.Errorf("coins and pools should not be both empty")
+	}
+	if len(cfg.Coins) != 0 && cg == nil {
+		return nil, fmt.Errorf("coingecko client should not be nil when watching coins")
+	}
+	if len(cfg.Pools) != 0 && gt == nil {
+		return nil, fmt.Errorf("geckoterminal client should not be nil when watching pools")
+	}
+	for _, pool := range cfg.Pools {
+		if pool.Network == "" || pool.PoolAddress == "" {
+			return nil, fmt.Errorf("pool should have network and address: %+v", pool)
+		}
+	}
+	if cfg.VsCurrency == "" {
+		cfg.VsCurrency = defaultVsCurrency
+	}
+	cfg.VsCurrency = strings.ToLower(cfg.VsCurrency)
+	if cfg.CoinInterval <= 0 {
+		cfg.CoinInterval = DefaultCoinInterval
+	}
+	if cfg.PoolInterval <= 0 {
+		cfg.PoolInterval = DefaultPoolInterval
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = defaultBuffer
+	}
+
+	return &Watcher{
+		cg:     cg,
+		gt:     gt,
+		cfg:    cfg,
+		events: make(chan Event, cfg.Buffer),
+		states: make(map[string]*state),
+		now:    time.Now,
+	}, nil
+}
+
+// Events returns the event channel, it is closed when Run returns.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Run polls immediately and then on the configured intervals until ctx is done. It should be called once.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.events)
+
+	var coinTick, poolTick <-chan time.Time
+	if len(w.cfg.Coins) != 0 {
+		ticker := time.NewTicker(w.cfg.CoinInterval)
+		defer ticker.Stop()
+		coinTick = ticker.C
+		w.pollCoins(ctx)
+	}
+	if len(w.cfg.Pools) != 0 {
+		ticker := time.NewTicker(w.cfg.PoolInterval)
+		defer ticker.Stop()
+		poolTick = ticker.C
+		w.pollPools(ctx)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-coinTick:
+			w.pollCoins(ctx)
+		case <-poolTick:
+			w.pollPools(ctx)
+		}
+	}
+}
+
+func (w *Watcher) pollCoins(ctx context.Context) {
+	vs := w.cfg.VsCurrency
+	for start := 0; start < len(w.cfg.Coins); start += maxCoinsPerRequest {
+		ids := w.cfg.Coins[start:min(start+maxCoinsPerRequest, len(w.cfg.Coins))]
+		data, err := w.cg.SimplePrice(ctx, ids, []string{vs}, "", "", "", "true", "")
+		if err != nil {
+			slog.Error("failed to poll coin prices", "error", err)
+			w.emit(ctx, Event{Type: EventError, Time: w.now(), Err: err})
+			continue
+		}
+		for _, id := range ids {
+			prices, ok := (*data)[id]
+			if !ok {
+				continue
+			}
+			price, ok := prices[vs]
+			if !ok {
+				continue
+			}
+			var updatedAt *time.Time
+			if ts, ok := prices["last_updated_at"]; ok {
+				t := time.Unix(int64(ts), 0).UTC()
+				updatedAt = &t
+			}
+			w.observe(ctx, Target{CoinID: id}, price, updatedAt)
+		}
+	}
+}
+
+func (w *Watcher) pollPools(ctx context.Context) {
+	byNetwork := make(map[string][]string)
+	for _, pool := range w.cfg.Pools {
+		byNetwork[pool.Network] = append(byNetwork[pool.Network], pool.PoolAddress)
+	}
+	networks := make([]string, 0, len(byNetwork))
+	for network := range byNetwork {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+
+	for _, network := range networks {
+		addresses := byNetwork[network]
+		for start := 0; start < len(addresses); start += maxPoolsPerRequest {
+			chunk := addresses[start:min(start+maxPoolsPerRequest, len(addresses))]
+			data, err := w.gt.GetMultiPools(ctx, network, nil, chunk)
+			if err != nil {
+				slog.Error("failed to poll pool prices", "error", err)
+				w.emit(ctx, Event{Type: EventError, Time: w.now(), Err: err})
+				continue
+			}
+			for _, pool := range data.Data {
+				price, err := pool.Attributes.BaseTokenPriceUSDDecimal()
+				if err != nil || price == nil {
+					continue
+				}
+				w.observe(ctx, Target{Network: network, PoolAddress: pool.Attributes.Address}, price.Float64(), nil)
+			}
+		}
+	}
+}
+
+// observe records a new price of target and emits the resulting events.
+func (w *Watcher) observe(ctx context.Context, target Target, price float64, updatedAt *time.Time) {
+	now := w.now()
+	key := target.Key()
+	s, seen := w.states[key]
+	if !seen {
+		s = &state{price: price, ref: price}
+		w.states[key] = s
+	}
+
+	prev := s.price
+	w.emit(ctx, Event{Type: EventPrice, Target: target, Time: now, Price: price, PrevPrice: prev,
+		ChangePercent: util.PercentChange(prev, price), UpdatedAt: updatedAt})
+
+	if seen {
+		if w.cfg.MovePercent > 0 && math.Abs(util.PercentChange(s.ref, price)) >= w.cfg.MovePercent {
+			w.emit(ctx, Event{Type: EventMove, Target: target, Time: now, Price: price, PrevPrice: s.ref,
+				ChangePercent: util.PercentChange(s.ref, price), UpdatedAt: updatedAt})
+			s.ref = price
+		}
+		for _, threshold := range w.cfg.Thresholds[key] {
+			if (prev < threshold && price >= threshold) || (prev > threshold && price <= threshold) {
+				w.emit(ctx, Event{Type: EventThreshold, Target: target, Time: now, Price: price, PrevPrice: prev,
+					ChangePercent: util.PercentChange(prev, price), Threshold: threshold, UpdatedAt: updatedAt})
+			}
+		}
+	}
+	s.price = price
+
+	if w.cfg.StaleAfter > 0 && updatedAt != nil {
+		stale := now.Sub(*updatedAt) > w.cfg.StaleAfter
+		if stale && !s.stale {
+			w.emit(ctx, Event{Type: EventStale, Target: target, Time: now, Price: price, UpdatedAt: updatedAt})
+		}
+		s.stale = stale
+	}
+}
+
+// emit sends event unless ctx is done.
+func (w *Watcher) emit(ctx context.Context, event Event) {
+	select {
+	case w.events <- event:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,176 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufdata/coingecko-api/coingecko"
+	"github.com/bufdata/coingecko-api/geckoterminal"
+)
+
+func eventTypes(events []Event) []EventType {
+	types := make([]EventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func drain(w *Watcher) []Event {
+	var events []Event
+	for {
+		select {
+		case event := <-w.events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestWatcher_Observe(t *testing.T) {
+	w, err := New(coingecko.NewCoinGecko("", false, nil), nil, Config{
+		Coins:       []string{"bitcoin"},
+		MovePercent: 5,
+		Thresholds:  map[string][]float64{"bitcoin": {105}},
+		StaleAfter:  time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	now := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	target := Target{CoinID: "bitcoin"}
+
+	cases := []struct {
+		name        string
+		price       float64
+		updatedAt   time.Time
+		wantedTypes []EventType
+	}{
+		{name: "first price", price: 100, updatedAt: now, wantedTypes: []EventType{EventPrice}},
+		{name: "small move", price: 103, updatedAt: now, wantedTypes: []EventType{EventPrice}},
+		{name: "move and crossing", price: 106, updatedAt: now, wantedTypes: []EventType{EventPrice, EventMove, EventThreshold}},
+		{name: "crossing down", price: 104, updatedAt: now, wantedTypes: []EventType{EventPrice, EventThreshold}},
+		{name: "stale", price: 104, updatedAt: now.Add(-2 * time.Minute), wantedTypes: []EventType{EventPrice, EventStale}},
+		{name: "still stale", price: 104, updatedAt: now.Add(-3 * time.Minute), wantedTypes: []EventType{EventPrice}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			updatedAt := tt.updatedAt
+			w.observe(context.TODO(), target, tt.price, &updatedAt)
+			if types := eventTypes(drain(w)); !reflect.DeepEqual(types, tt.wantedTypes) {
+				t.Fatalf("incorrect events, wanted: %v, got: %v", tt.wantedTypes, types)
+			}
+		})
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	paths := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths[r.URL.Path]++
+		if strings.HasSuffix(r.URL.Path, "/simple/price") {
+			_, _ = fmt.Fprintf(w, `{"bitcoin":{"usd":40000,"last_updated_at":%d},"ethereum":{"usd":2000}}`, time.Now().Unix())
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"eth_0xpool","type":"pool","attributes":{"address":"0xpool","base_token_price_usd":"2000.5"}}]}`))
+	}))
+	defer server.Close()
+
+	cg := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	gt := geckoterminal.NewGeckoTerminal(nil, geckoterminal.WithBaseURL(server.URL+"/api/v2"))
+	w, err := New(cg, gt, Config{
+		Coins: []string{"bitcoin", "ethereum"},
+		Pools: []Target{{Network: "eth", PoolAddress: "0xpool"}},
+	})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	prices := make(map[string]float64)
+	for event := range w.Events() {
+		if event.Type != EventPrice {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+		prices[event.Target.Key()] = event.Price
+		if len(prices) == 3 {
+			cancel()
+		}
+	}
+	if err = <-done; err != context.Canceled {
+		t.Fatalf("run should return context.Canceled, got: %v", err)
+	}
+
+	wanted := map[string]float64{"bitcoin": 40000, "ethereum": 2000, "eth_0xpool": 2000.5}
+	if !reflect.DeepEqual(prices, wanted) {
+		t.Fatalf("incorrect prices, wanted: %v, got: %v", wanted, prices)
+	}
+	if paths["/api/v3/simple/price"] != 1 || paths["/api/v2/networks/eth/pools/multi/0xpool"] != 1 {
+		t.Fatalf("each cycle should send one batched request, got: %v", paths)
+	}
+}
+
+func TestWatcher_PollCoinsChunkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Query().Get("ids"), "coin0,") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"coin100":{"usd":1}}`))
+	}))
+	defer server.Close()
+
+	coins := make([]string, maxCoinsPerRequest+1)
+	for i := range coins {
+		coins[i] = fmt.Sprintf("coin%d", i)
+	}
+	cg := coingecko.NewCoinGecko("", false, nil, coingecko.WithBaseURL(server.URL+"/api/v3"))
+	w, err := New(cg, nil, Config{Coins: coins})
+	if err != nil {
+		t.Fatalf("error should be nil, got: %v", err)
+	}
+	w.pollCoins(context.TODO())
+	events := drain(w)
+	if types := eventTypes(events); !reflect.DeepEqual(types, []EventType{EventError, EventPrice}) ||
+		events[1].Target.CoinID != "coin100" {
+		t.Fatalf("a failed chunk should not stop the other chunks, got: %+v", events)
+	}
+}
+
+func TestTarget_Key(t *testing.T) {
+	cases := []struct {
+		target Target
+		wanted string
+	}{
+		{target: Target{CoinID: "bitcoin"}, wanted: "bitcoin"},
+		{target: Target{Network: "eth", PoolAddress: "0xABC"}, wanted: "eth_0xabc"},
+		{target: Target{Network: "solana", PoolAddress: "Czfq3xZZDmsdGdUyrNLtRhGc47cXcZtLG4crryfu44zE"},
+			wanted: "solana_Czfq3xZZDmsdGdUyrNLtRhGc47cXcZtLG4crryfu44zE"},
+	}
+	for _, tt := range cases {
+		if result := tt.target.Key(); result != tt.wanted {
+			t.Fatalf("incorrect key, wanted: %s, got: %s", tt.wanted, result)
+		}
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := New(nil, nil, Config{}); err == nil {
+		t.Fatal("error should not be nil")
+	}
+	if _, err := New(nil, nil, Config{Pools: []Target{{Network: "eth", PoolAddress: "0xpool"}}}); err == nil {
+		t.Fatal("error should not be nil")
+	}
+}